@@ -4,12 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"log"
-	"math"
 	"os"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"aoc2022/interval"
 )
 
 type Point struct {
@@ -23,11 +23,6 @@ type Sensor struct {
 	sRange int
 }
 
-type Range struct {
-	min int
-	max int
-}
-
 func abs(n int) int {
 	if n < 0 {
 		return 0 - n
@@ -85,21 +80,27 @@ func calculateSensorWidthAtY(sensor Sensor, distanceFromY int) int {
 	return (2*sensor.sRange + 1) - (2 * distanceFromY)
 }
 
-func countSensorCoveredPosAtY(sensors []Sensor, targetY int) int {
-	maxX := math.MinInt
-	minX := math.MaxInt
+func sensorRangeAtY(sensor Sensor, targetY int) (interval.Interval[int], bool) {
+	sensorWidthAtTarget := calculateSensorWidthAtY(sensor, abs(targetY-sensor.pos.y))
+	if sensorWidthAtTarget <= 0 {
+		return interval.Interval[int]{}, false
+	}
+	halfWidth := (sensorWidthAtTarget - 1) / 2
+	return interval.New(sensor.pos.x-halfWidth, sensor.pos.x+halfWidth), true
+}
+
+func sensorCoverageAtY(sensors []Sensor, targetY int) interval.IntervalSet[int] {
+	coverage := interval.IntervalSet[int]{}
 	for _, sensor := range sensors {
-		sensorWidthAtTarget := calculateSensorWidthAtY(sensor, abs(targetY-sensor.pos.y))
-		halfWidth := (sensorWidthAtTarget - 1) / 2
-		if sensor.pos.x-halfWidth < minX {
-			minX = sensor.pos.x - halfWidth
-		}
-		if sensor.pos.x+halfWidth > maxX {
-			maxX = sensor.pos.x + halfWidth
+		if xRange, ok := sensorRangeAtY(sensor, targetY); ok {
+			coverage.Insert(xRange)
 		}
 	}
-	// add one because counting from 0th x value
-	return maxX - minX + 1
+	return coverage
+}
+
+func countSensorCoveredPosAtY(sensors []Sensor, targetY int) int {
+	return sensorCoverageAtY(sensors, targetY).Len()
 }
 
 func beaconsOnTargetY(sensors []Sensor, targetY int) int {
@@ -116,46 +117,15 @@ func calculateTuningFreq(p Point) int {
 	return p.x*4000000 + p.y
 }
 
-func rangeBreakPos(ranges []Range) int {
-	sort.Slice(ranges, func(i, j int) bool {
-		if ranges[i].min == ranges[j].min {
-			return ranges[i].max < ranges[j].max
-		}
-		return ranges[i].min < ranges[j].min
-	})
-	maxX := ranges[0].max
-	for i := 1; i < len(ranges); i++ {
-		if maxX < ranges[i].min {
-			return maxX + 1
-		} else if maxX < ranges[i].max {
-			maxX = ranges[i].max
-		}
-	}
-	return -1
-}
-
 func findDistressBeacon(sensors []Sensor, maxY int) Point {
-	rangeBreakX := -1
-	y := 0
-	for ; y <= maxY; y++ {
-		xRanges := make([]Range, 0)
-		for _, sensor := range sensors {
-			sensorWidthAtTarget := calculateSensorWidthAtY(sensor, abs(y-sensor.pos.y))
-			if sensorWidthAtTarget > 0 {
-				halfWidth := (sensorWidthAtTarget - 1) / 2
-				xRange := Range{
-					min: sensor.pos.x - halfWidth,
-					max: sensor.pos.x + halfWidth,
-				}
-				xRanges = append(xRanges, xRange)
-			}
-		}
-		rangeBreakX = rangeBreakPos(xRanges)
-		if rangeBreakX > -1 {
-			break
+	searchArea := interval.New(0, maxY)
+	for y := 0; y <= maxY; y++ {
+		gaps := sensorCoverageAtY(sensors, y).Gaps(searchArea)
+		if len(gaps) > 0 {
+			return Point{x: gaps[0].Min, y: y}
 		}
 	}
-	return Point{x: rangeBreakX, y: y}
+	return Point{x: -1, y: maxY + 1}
 }
 
 func main() {
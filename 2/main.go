@@ -2,12 +2,23 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"time"
 )
 
+type Shape int
+
+const (
+	ROCK Shape = iota
+	PAPER
+	SCISSORS
+)
+
+const SHAPE_COUNT = 3
+
 func parseRpsRounds(scanner *bufio.Scanner) []string {
 	rpsRounds := make([]string, 0)
 	for scanner.Scan() {
@@ -39,7 +50,70 @@ func calculateTotalScore(rpsRounds []string, roundScoring map[string]int) int {
 	return score
 }
 
+func opponentShape(round string) Shape {
+	return Shape(round[0] - 'A')
+}
+
+// the guide's second column is the outcome to aim for (X lose, Y draw, Z win)
+func guideShape(round string) Shape {
+	outcome := Shape(round[2] - 'X')
+	return (opponentShape(round) + outcome + SHAPE_COUNT - 1) % SHAPE_COUNT
+}
+
+func winningShape(opponent Shape) Shape {
+	return (opponent + 1) % SHAPE_COUNT
+}
+
+func scoreRound(opponent Shape, own Shape) int {
+	score := int(own) + 1
+	switch (own - opponent + SHAPE_COUNT) % SHAPE_COUNT {
+	case 0:
+		score += 3
+	case 1:
+		score += 6
+	}
+	return score
+}
+
+func findGuideShapes(rpsRounds []string) []Shape {
+	shapes := make([]Shape, 0)
+	for _, round := range rpsRounds {
+		shapes = append(shapes, guideShape(round))
+	}
+	return shapes
+}
+
+func findOptimalShapes(rpsRounds []string) []Shape {
+	shapes := make([]Shape, 0)
+	for _, round := range rpsRounds {
+		shapes = append(shapes, winningShape(opponentShape(round)))
+	}
+	return shapes
+}
+
+func scoreShapes(rpsRounds []string, shapes []Shape) int {
+	score := 0
+	for i, round := range rpsRounds {
+		score += scoreRound(opponentShape(round), shapes[i])
+	}
+	return score
+}
+
+func countDifferentShapes(a []Shape, b []Shape) int {
+	count := 0
+	for i := range a {
+		if a[i] != b[i] {
+			count++
+		}
+	}
+	return count
+}
+
 func main() {
+	trials := flag.Int("trials", 1000, "number of simulated games per opponent model")
+	seed := flag.Int64("seed", 1, "seed for the opponent simulations")
+	flag.Parse()
+
 	start := time.Now()
 	file, err := os.Open("input.txt")
 	if err != nil {
@@ -55,8 +129,18 @@ func main() {
 	rpsRounds := parseRpsRounds(scanner)
 	roundScoring := createRoundScoring()
 	totalScore := calculateTotalScore(rpsRounds, roundScoring)
+	guideShapes := findGuideShapes(rpsRounds)
+	optimalShapes := findOptimalShapes(rpsRounds)
+	optimalScore := scoreShapes(rpsRounds, optimalShapes)
+	differentShapes := countDifferentShapes(guideShapes, optimalShapes)
+	simulations := simulateOpponentModels(guideShapes, *trials, *seed)
 
 	elapsed := time.Since(start)
 	fmt.Println(totalScore)
+	fmt.Println("Optimal score:", optimalScore)
+	fmt.Printf("Guide differs from optimal in %d of %d rounds (%d points short)\n", differentShapes, len(rpsRounds), optimalScore-totalScore)
+	for _, simulation := range simulations {
+		fmt.Printf("%s opponent: mean score %.2f, variance %.2f\n", simulation.model, simulation.mean, simulation.variance)
+	}
 	log.Printf("Time taken: %s", elapsed)
 }
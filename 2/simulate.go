@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math/rand"
+)
+
+// an opponent model picks its next shape knowing only the shapes we have
+// played so far, along with how often we have played each of them
+type OpponentModel struct {
+	name      string
+	nextShape func(ownHistory []Shape, ownCounts []int, rng *rand.Rand) Shape
+}
+
+type SimulationResult struct {
+	model    string
+	mean     float64
+	variance float64
+}
+
+func randomShape(rng *rand.Rand) Shape {
+	return Shape(rng.Intn(SHAPE_COUNT))
+}
+
+func randomOpponent(ownHistory []Shape, ownCounts []int, rng *rand.Rand) Shape {
+	return randomShape(rng)
+}
+
+// plays whatever beats our most common shape so far, breaking ties randomly
+func frequencyOpponent(ownHistory []Shape, ownCounts []int, rng *rand.Rand) Shape {
+	if len(ownHistory) == 0 {
+		return randomShape(rng)
+	}
+	mostCommon := make([]Shape, 0)
+	highestCount := 0
+	for shape, count := range ownCounts {
+		if count > highestCount {
+			highestCount = count
+			mostCommon = []Shape{Shape(shape)}
+		} else if count == highestCount {
+			mostCommon = append(mostCommon, Shape(shape))
+		}
+	}
+	return winningShape(mostCommon[rng.Intn(len(mostCommon))])
+}
+
+func copyLastOpponent(ownHistory []Shape, ownCounts []int, rng *rand.Rand) Shape {
+	if len(ownHistory) == 0 {
+		return randomShape(rng)
+	}
+	return ownHistory[len(ownHistory)-1]
+}
+
+func createOpponentModels() []OpponentModel {
+	return []OpponentModel{
+		{name: "random", nextShape: randomOpponent},
+		{name: "frequency", nextShape: frequencyOpponent},
+		{name: "copy-last", nextShape: copyLastOpponent},
+	}
+}
+
+// the counts are kept as we go so models don't have to rescan the history
+// every round
+func playGame(shapes []Shape, model OpponentModel, rng *rand.Rand) int {
+	score := 0
+	counts := make([]int, SHAPE_COUNT)
+	for i, shape := range shapes {
+		score += scoreRound(model.nextShape(shapes[:i], counts, rng), shape)
+		counts[shape]++
+	}
+	return score
+}
+
+func meanAndVariance(scores []int) (float64, float64) {
+	sum := 0.0
+	for _, score := range scores {
+		sum += float64(score)
+	}
+	mean := sum / float64(len(scores))
+	squaredDiffs := 0.0
+	for _, score := range scores {
+		diff := float64(score) - mean
+		squaredDiffs += diff * diff
+	}
+	return mean, squaredDiffs / float64(len(scores))
+}
+
+// each model gets its own rng from the same seed so results don't depend on
+// the order the models are run in
+func simulateOpponentModels(shapes []Shape, trials int, seed int64) []SimulationResult {
+	results := make([]SimulationResult, 0)
+	if trials <= 0 {
+		return results
+	}
+	for _, model := range createOpponentModels() {
+		rng := rand.New(rand.NewSource(seed))
+		scores := make([]int, trials)
+		for i := range scores {
+			scores[i] = playGame(shapes, model, rng)
+		}
+		mean, variance := meanAndVariance(scores)
+		results = append(results, SimulationResult{model: model.name, mean: mean, variance: variance})
+	}
+	return results
+}
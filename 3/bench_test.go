@@ -0,0 +1,141 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+const (
+	BENCH_COMPARTMENT_SIZE = 24
+	BENCH_GROUP_SIZE       = 3
+	BENCH_GROUP_COUNT      = 10000
+)
+
+// map based item sets, kept as the baseline for the bitmask benchmarks
+
+func createRucksackSet(rucksack string) map[rune]struct{} {
+	ruckSackSet := make(map[rune]struct{})
+	for _, char := range rucksack {
+		ruckSackSet[char] = struct{}{}
+	}
+	return ruckSackSet
+}
+
+// assumes there's only one occurence of a char that
+// matches every rucksack in the group
+func findGroupCommonType(rucksackGroup []string) rune {
+	commonChar := ' '
+	lastSack := len(rucksackGroup) - 1
+	sackSets := make([]map[rune]struct{}, 0)
+	for _, rucksack := range rucksackGroup[:lastSack] {
+		sackSets = append(sackSets, createRucksackSet(rucksack))
+	}
+	for _, char := range rucksackGroup[lastSack] {
+		foundInAll := true
+		for _, sackSet := range sackSets {
+			_, found := sackSet[char]
+			foundInAll = foundInAll && found
+		}
+		if foundInAll {
+			commonChar = char
+		}
+	}
+	return commonChar
+}
+
+func findCommonTypes(rucksackGroups [][]string) []rune {
+	commonTypes := make([]rune, 0)
+	for _, rucksackGroup := range rucksackGroups {
+		commonTypes = append(commonTypes, findGroupCommonType(rucksackGroup))
+	}
+	return commonTypes
+}
+
+func sumCommonTypePriorites(commonTypes []rune) int {
+	sum := 0
+	for _, commonType := range commonTypes {
+		sum += itemPriority(commonType)
+	}
+	return sum
+}
+
+func randomCompartment(pool []byte, rng *rand.Rand, forced ...byte) string {
+	var compartment strings.Builder
+	compartment.Write(forced)
+	for compartment.Len() < BENCH_COMPARTMENT_SIZE {
+		compartment.WriteByte(pool[rng.Intn(len(pool))])
+	}
+	return compartment.String()
+}
+
+// every group shares exactly one badge and every rucksack's compartments
+// share exactly one item, by giving each rucksack in a group its own disjoint
+// pool of item types
+func generateRucksacks(groupCount int, groupSize int, rng *rand.Rand) []string {
+	rucksacks := make([]string, 0, groupCount*groupSize)
+	for g := 0; g < groupCount; g++ {
+		itemTypes := []byte(ITEM_TYPES)
+		rng.Shuffle(len(itemTypes), func(i, j int) {
+			itemTypes[i], itemTypes[j] = itemTypes[j], itemTypes[i]
+		})
+		badge := itemTypes[0]
+		poolSize := (len(itemTypes) - 1) / groupSize
+		for i := 0; i < groupSize; i++ {
+			pool := itemTypes[1+i*poolSize : 1+(i+1)*poolSize]
+			shared := pool[0]
+			half := (len(pool) - 1) / 2
+			first := randomCompartment(pool[1:1+half], rng, badge, shared)
+			second := randomCompartment(pool[1+half:], rng, shared)
+			rucksacks = append(rucksacks, first+second)
+		}
+	}
+	return rucksacks
+}
+
+func benchRucksacks(b *testing.B) []string {
+	b.Helper()
+	rng := rand.New(rand.NewSource(1))
+	return generateRucksacks(BENCH_GROUP_COUNT, BENCH_GROUP_SIZE, rng)
+}
+
+func benchRucksackGroups(b *testing.B) [][]string {
+	b.Helper()
+	rucksackGroups, err := parseRucksackGroups(benchRucksacks(b), BENCH_GROUP_SIZE)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return rucksackGroups
+}
+
+func BenchmarkCompartmentsMap(b *testing.B) {
+	compartmentGroups := parseCompartmentGroups(benchRucksacks(b))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sumCommonTypePriorites(findCommonTypes(compartmentGroups))
+	}
+}
+
+func BenchmarkCompartmentsBitmask(b *testing.B) {
+	compartmentGroups := parseCompartmentGroups(benchRucksacks(b))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sumCommonItemPriorities(findCommonItems(compartmentGroups))
+	}
+}
+
+func BenchmarkGroupsMap(b *testing.B) {
+	rucksackGroups := benchRucksackGroups(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sumCommonTypePriorites(findCommonTypes(rucksackGroups))
+	}
+}
+
+func BenchmarkGroupsBitmask(b *testing.B) {
+	rucksackGroups := benchRucksackGroups(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sumCommonItemPriorities(findCommonItems(rucksackGroups))
+	}
+}
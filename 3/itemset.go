@@ -0,0 +1,63 @@
+package main
+
+import (
+	"math/bits"
+)
+
+const ITEM_TYPES = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// one bit per item type, a-z in bits 0-25 and A-Z in bits 26-51, so an
+// item's priority is its bit index plus one
+type ItemSet uint64
+
+func itemPriority(item rune) int {
+	if item >= 'a' && item <= 'z' {
+		return int(item-'a') + 1
+	} else if item >= 'A' && item <= 'Z' {
+		return int(item-'A') + 27
+	}
+	return 0
+}
+
+func createItemSet(rucksack string) ItemSet {
+	var itemSet ItemSet
+	for _, item := range rucksack {
+		if priority := itemPriority(item); priority > 0 {
+			itemSet |= 1 << (priority - 1)
+		}
+	}
+	return itemSet
+}
+
+func (s ItemSet) intersect(other ItemSet) ItemSet {
+	return s & other
+}
+
+func (s ItemSet) count() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+func (s ItemSet) priorities() []int {
+	priorities := make([]int, 0, s.count())
+	for s != 0 {
+		priorities = append(priorities, bits.TrailingZeros64(uint64(s))+1)
+		s &= s - 1
+	}
+	return priorities
+}
+
+func (s ItemSet) prioritySum() int {
+	sum := 0
+	for _, priority := range s.priorities() {
+		sum += priority
+	}
+	return sum
+}
+
+func (s ItemSet) items() string {
+	items := make([]byte, 0, s.count())
+	for _, priority := range s.priorities() {
+		items = append(items, ITEM_TYPES[priority-1])
+	}
+	return string(items)
+}
@@ -2,80 +2,78 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"time"
 )
 
-func parseRucksackGroups(scanner *bufio.Scanner) [][]string {
-	rucksackGroups := make([][]string, 0)
-	rucksackGroup := make([]string, 0)
+func parseRucksacks(scanner *bufio.Scanner) []string {
+	rucksacks := make([]string, 0)
 	for scanner.Scan() {
-		line := scanner.Text()
-		rucksackGroup = append(rucksackGroup, line)
-		if len(rucksackGroup) == 3 {
-			rucksackGroups = append(rucksackGroups, rucksackGroup)
-			rucksackGroup = make([]string, 0)
-		}
+		rucksacks = append(rucksacks, scanner.Text())
 	}
-	return rucksackGroups
+	return rucksacks
 }
 
-func createRucksackSet(rucksack string) map[rune]struct{} {
-	ruckSackSet := make(map[rune]struct{})
-	for _, char := range rucksack {
-		ruckSackSet[char] = struct{}{}
+func parseRucksackGroups(rucksacks []string, groupSize int) ([][]string, error) {
+	if groupSize <= 0 {
+		return nil, fmt.Errorf("group size must be positive, got %d", groupSize)
+	}
+	if len(rucksacks)%groupSize != 0 {
+		return nil, fmt.Errorf("%d rucksacks can't be split into groups of %d", len(rucksacks), groupSize)
+	}
+	rucksackGroups := make([][]string, 0)
+	for i := 0; i < len(rucksacks); i += groupSize {
+		rucksackGroups = append(rucksackGroups, rucksacks[i:i+groupSize])
 	}
-	return ruckSackSet
+	return rucksackGroups, nil
 }
 
-// assumes there's only one occurence of a char that
-// matches all three
-func findGroupCommonType(rucksackGroup []string) rune {
-	commonChar := ' '
-	firstSackSet := createRucksackSet(rucksackGroup[0])
-	secondSackSet := createRucksackSet(rucksackGroup[1])
-	for _, char := range rucksackGroup[2] {
-		_, firstFound := firstSackSet[char]
-		_, secondFound := secondSackSet[char]
-		if firstFound && secondFound {
-			commonChar = char
-		}
+// treats each rucksack's two compartments as a group of two
+func parseCompartmentGroups(rucksacks []string) [][]string {
+	compartmentGroups := make([][]string, 0)
+	for _, rucksack := range rucksacks {
+		half := len(rucksack) / 2
+		compartmentGroups = append(compartmentGroups, []string{rucksack[:half], rucksack[half:]})
 	}
-	return commonChar
+	return compartmentGroups
 }
 
-func findCommonTypes(rucksackGroups [][]string) []rune {
-	commonTypes := make([]rune, 0)
-	for _, rucksackGroup := range rucksackGroups {
-		commonTypes = append(commonTypes, findGroupCommonType(rucksackGroup))
+func findGroupCommonItems(rucksackGroup []string) ItemSet {
+	commonItems := createItemSet(rucksackGroup[0])
+	for _, rucksack := range rucksackGroup[1:] {
+		commonItems = commonItems.intersect(createItemSet(rucksack))
 	}
-	return commonTypes
+	return commonItems
 }
 
-func commonTypesToPriorities(commonTypes []rune) []int {
-	priorities := make([]int, 0)
-	for _, commonType := range commonTypes {
-		if commonType >= 97 {
-			priorities = append(priorities, int(commonType)-96)
-		} else {
-			priorities = append(priorities, int(commonType)-38)
-		}
+func findCommonItems(rucksackGroups [][]string) []ItemSet {
+	commonItems := make([]ItemSet, 0)
+	for _, rucksackGroup := range rucksackGroups {
+		commonItems = append(commonItems, findGroupCommonItems(rucksackGroup))
 	}
-	return priorities
+	return commonItems
 }
 
-func sumCommonTypePriorites(commonTypes []rune) int {
+func sumCommonItemPriorities(commonItems []ItemSet) int {
 	sum := 0
-	priorities := commonTypesToPriorities(commonTypes)
-	for _, priority := range priorities {
-		sum += priority
+	for _, items := range commonItems {
+		sum += items.prioritySum()
 	}
 	return sum
 }
 
 func main() {
+	mode := flag.String("mode", "both", "which priorities to sum: compartment, group or both")
+	groupSize := flag.Int("group", 3, "number of elves in each badge group")
+	strict := flag.Bool("strict", false, "fail on invalid items or groups that don't share exactly one item")
+	flag.Parse()
+	if *mode != "compartment" && *mode != "group" && *mode != "both" {
+		log.Fatalf("unknown mode %q, expected compartment, group or both", *mode)
+	}
+
 	start := time.Now()
 	file, err := os.Open("input.txt")
 	if err != nil {
@@ -88,11 +86,35 @@ func main() {
 	}()
 
 	scanner := bufio.NewScanner(file)
-	rucksacksGroups := parseRucksackGroups(scanner)
-	commonTypes := findCommonTypes(rucksacksGroups)
-	prioritySum := sumCommonTypePriorites(commonTypes)
+	rucksacks := parseRucksacks(scanner)
+	if *mode == "compartment" || *mode == "both" {
+		prioritySum := 0
+		if *strict {
+			prioritySum, err = sumCompartmentPrioritiesStrict(rucksacks)
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			compartmentGroups := parseCompartmentGroups(rucksacks)
+			prioritySum = sumCommonItemPriorities(findCommonItems(compartmentGroups))
+		}
+		fmt.Println("Compartment priority sum:", prioritySum)
+	}
+	if *mode == "group" || *mode == "both" {
+		prioritySum := 0
+		if *strict {
+			prioritySum, err = sumGroupPrioritiesStrict(rucksacks, *groupSize)
+		} else {
+			var rucksacksGroups [][]string
+			rucksacksGroups, err = parseRucksackGroups(rucksacks, *groupSize)
+			prioritySum = sumCommonItemPriorities(findCommonItems(rucksacksGroups))
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Group priority sum:", prioritySum)
+	}
 
 	elapsed := time.Since(start)
-	fmt.Println(prioritySum)
 	log.Printf("Time taken: %s", elapsed)
 }
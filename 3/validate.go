@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func describeLines(firstLine int, lineCount int) string {
+	if lineCount == 1 {
+		return fmt.Sprintf("line %d", firstLine)
+	}
+	return fmt.Sprintf("lines %d-%d", firstLine, firstLine+lineCount-1)
+}
+
+func findInvalidItems(rucksacks []string) []string {
+	issues := make([]string, 0)
+	for i, rucksack := range rucksacks {
+		// ranging over a string gives byte offsets, count runes so the
+		// position is right after a multi-byte item
+		position := 0
+		for _, item := range rucksack {
+			position++
+			if itemPriority(item) == 0 {
+				issues = append(issues, fmt.Sprintf("line %d: invalid item %q at position %d", i+1, item, position))
+			}
+		}
+	}
+	return issues
+}
+
+func findUnevenRucksacks(rucksacks []string) []string {
+	issues := make([]string, 0)
+	for i, rucksack := range rucksacks {
+		if len(rucksack)%2 != 0 {
+			issues = append(issues, fmt.Sprintf("line %d: %d items can't be split into two compartments", i+1, len(rucksack)))
+		}
+	}
+	return issues
+}
+
+// linesPerGroup is 1 for compartments, since both halves come from one line
+func findCommonItemIssues(commonItems []ItemSet, linesPerGroup int) []string {
+	issues := make([]string, 0)
+	for i, items := range commonItems {
+		lines := describeLines(i*linesPerGroup+1, linesPerGroup)
+		if items.count() == 0 {
+			issues = append(issues, fmt.Sprintf("%s: no shared item", lines))
+		} else if items.count() > 1 {
+			issues = append(issues, fmt.Sprintf("%s: %d shared items %q", lines, items.count(), items.items()))
+		}
+	}
+	return issues
+}
+
+func issuesToError(issues []string) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d invalid rucksacks or groups:\n%s", len(issues), strings.Join(issues, "\n"))
+}
+
+func sumCompartmentPrioritiesStrict(rucksacks []string) (int, error) {
+	issues := append(findInvalidItems(rucksacks), findUnevenRucksacks(rucksacks)...)
+	if len(issues) > 0 {
+		return 0, issuesToError(issues)
+	}
+	commonItems := findCommonItems(parseCompartmentGroups(rucksacks))
+	if err := issuesToError(findCommonItemIssues(commonItems, 1)); err != nil {
+		return 0, err
+	}
+	return sumCommonItemPriorities(commonItems), nil
+}
+
+func sumGroupPrioritiesStrict(rucksacks []string, groupSize int) (int, error) {
+	if err := issuesToError(findInvalidItems(rucksacks)); err != nil {
+		return 0, err
+	}
+	rucksackGroups, err := parseRucksackGroups(rucksacks, groupSize)
+	if err != nil {
+		return 0, err
+	}
+	commonItems := findCommonItems(rucksackGroups)
+	if err := issuesToError(findCommonItemIssues(commonItems, groupSize)); err != nil {
+		return 0, err
+	}
+	return sumCommonItemPriorities(commonItems), nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"aoc2022/interval"
+)
+
+// a run of consecutive sections all assigned to the same number of elves
+type CoverageSegment struct {
+	sections SectionAssignment
+	elves    int
+}
+
+type CoverageReport struct {
+	coveredByAtLeastK interval.IntervalSet[int]
+	maxElves          int
+	busiestSections   []SectionAssignment
+	uncovered         []SectionAssignment
+	redundantPairs    []int
+}
+
+func flattenAssignments(pairs []Pair[SectionAssignment, SectionAssignment]) []SectionAssignment {
+	assignments := make([]SectionAssignment, 0)
+	for _, pair := range pairs {
+		assignments = append(assignments, pair.first, pair.second)
+	}
+	return assignments
+}
+
+// sweep line over assignment start and end events, only keeping segments
+// that at least one elf covers
+func sweepCoverage(assignments []SectionAssignment) []CoverageSegment {
+	deltas := make(map[int]int)
+	for _, assignment := range assignments {
+		deltas[assignment.Min] += 1
+		deltas[assignment.Max+1] -= 1
+	}
+	positions := make([]int, 0)
+	for pos := range deltas {
+		positions = append(positions, pos)
+	}
+	sort.Ints(positions)
+
+	segments := make([]CoverageSegment, 0)
+	elves := 0
+	for i := 0; i+1 < len(positions); i++ {
+		pos := positions[i]
+		elves += deltas[pos]
+		if elves > 0 {
+			segments = append(segments, CoverageSegment{
+				sections: interval.New(pos, positions[i+1]-1),
+				elves:    elves,
+			})
+		}
+	}
+	return segments
+}
+
+func sectionsCoveredByAtLeast(segments []CoverageSegment, k int) interval.IntervalSet[int] {
+	covered := interval.IntervalSet[int]{}
+	for _, segment := range segments {
+		if segment.elves >= k {
+			covered.Insert(segment.sections)
+		}
+	}
+	return covered
+}
+
+func findBusiestSections(segments []CoverageSegment) (int, []SectionAssignment) {
+	maxElves := 0
+	busiest := interval.IntervalSet[int]{}
+	for _, segment := range segments {
+		if segment.elves > maxElves {
+			maxElves = segment.elves
+			busiest = interval.NewSet(segment.sections)
+		} else if segment.elves == maxElves {
+			busiest.Insert(segment.sections)
+		}
+	}
+	return maxElves, busiest.Intervals()
+}
+
+func ownCoverage(pair Pair[SectionAssignment, SectionAssignment], sections SectionAssignment) int {
+	own := 0
+	if pair.first.ContainsInterval(sections) {
+		own++
+	}
+	if pair.second.ContainsInterval(sections) {
+		own++
+	}
+	return own
+}
+
+// a pair is redundant when every section it covers is also covered by
+// some other elf outside the pair. Segment boundaries include every
+// assignment's ends, so a segment is either fully inside an assignment or
+// outside it.
+func findRedundantPairs(pairs []Pair[SectionAssignment, SectionAssignment], segments []CoverageSegment) []int {
+	redundant := make([]int, 0)
+	for i, pair := range pairs {
+		isRedundant := true
+		for _, segment := range segments {
+			own := ownCoverage(pair, segment.sections)
+			if own > 0 && segment.elves-own < 1 {
+				isRedundant = false
+				break
+			}
+		}
+		if isRedundant {
+			redundant = append(redundant, i)
+		}
+	}
+	return redundant
+}
+
+func findCampSpan(segments []CoverageSegment) SectionAssignment {
+	return interval.New(segments[0].sections.Min, segments[len(segments)-1].sections.Max)
+}
+
+func analyseCoverage(pairs []Pair[SectionAssignment, SectionAssignment], k int, span *SectionAssignment) CoverageReport {
+	segments := sweepCoverage(flattenAssignments(pairs))
+	uncovered := make([]SectionAssignment, 0)
+	if span != nil {
+		uncovered = sectionsCoveredByAtLeast(segments, 1).Gaps(*span)
+	} else if len(segments) > 0 {
+		uncovered = sectionsCoveredByAtLeast(segments, 1).Gaps(findCampSpan(segments))
+	}
+	maxElves, busiest := findBusiestSections(segments)
+	return CoverageReport{
+		coveredByAtLeastK: sectionsCoveredByAtLeast(segments, k),
+		maxElves:          maxElves,
+		busiestSections:   busiest,
+		uncovered:         uncovered,
+		redundantPairs:    findRedundantPairs(pairs, segments),
+	}
+}
+
+func formatSections(sections []SectionAssignment) string {
+	formatted := make([]string, 0)
+	for _, s := range sections {
+		formatted = append(formatted, fmt.Sprintf("%d-%d", s.Min, s.Max))
+	}
+	return strings.Join(formatted, ",")
+}
+
+func printCoverageReport(report CoverageReport, k int) {
+	fmt.Printf("Sections covered by at least %d elves: %d (%s)\n", k, report.coveredByAtLeastK.Len(), formatSections(report.coveredByAtLeastK.Intervals()))
+	fmt.Printf("Most elves on one section: %d (%s)\n", report.maxElves, formatSections(report.busiestSections))
+	fmt.Printf("Uncovered sections: %s\n", formatSections(report.uncovered))
+	redundantLines := make([]string, 0)
+	for _, i := range report.redundantPairs {
+		redundantLines = append(redundantLines, fmt.Sprint(i+1))
+	}
+	fmt.Printf("Redundant pairs (by line): %s\n", strings.Join(redundantLines, ","))
+}
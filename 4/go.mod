@@ -1,3 +1,7 @@
 module main
 
 go 1.18
+
+require aoc2022/interval v0.0.0
+
+replace aoc2022/interval => ../interval
@@ -2,17 +2,31 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"aoc2022/interval"
+)
+
+type SectionAssignment = interval.Interval[int]
+
+type PairRelation int
+
+const (
+	DISJOINT PairRelation = iota
+	TOUCHING
+	OVERLAPPING
+	CONTAINING
+	IDENTICAL
 )
 
-type SectionAssignment struct {
-	lower int
-	upper int
+func (r PairRelation) String() string {
+	return [...]string{"disjoint", "touching", "overlapping", "containing", "identical"}[r]
 }
 
 type Pair[T, U any] struct {
@@ -24,10 +38,25 @@ func parseSectionAssignment(assignment string) SectionAssignment {
 	assignmentSplit := strings.Split(assignment, "-")
 	lower, _ := strconv.Atoi(assignmentSplit[0])
 	upper, _ := strconv.Atoi(assignmentSplit[1])
-	return SectionAssignment{
-		lower: lower,
-		upper: upper,
+	return interval.New(lower, upper)
+}
+
+// parseSpan is the checked version of parseSectionAssignment for the -span
+// flag, which unlike the puzzle input can't be trusted to be well formed
+func parseSpan(span string) (SectionAssignment, error) {
+	bounds := strings.Split(span, "-")
+	if len(bounds) != 2 {
+		return SectionAssignment{}, fmt.Errorf("span must look like 1-99, got %q", span)
 	}
+	lower, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		return SectionAssignment{}, fmt.Errorf("invalid span start %q: %w", bounds[0], err)
+	}
+	upper, err := strconv.Atoi(bounds[1])
+	if err != nil {
+		return SectionAssignment{}, fmt.Errorf("invalid span end %q: %w", bounds[1], err)
+	}
+	return interval.New(lower, upper), nil
 }
 
 func parseSectionAssignmentPairs(scanner *bufio.Scanner) []Pair[SectionAssignment, SectionAssignment] {
@@ -43,16 +72,10 @@ func parseSectionAssignmentPairs(scanner *bufio.Scanner) []Pair[SectionAssignmen
 	return sectionAssignmentPairs
 }
 
-func isOverlapping(a SectionAssignment, b SectionAssignment) bool {
-	return (a.upper >= b.lower) && (a.lower <= b.lower)
-}
-
 func countOverlapTotal(pairs []Pair[SectionAssignment, SectionAssignment]) int {
 	total := 0
 	for _, pair := range pairs {
-		if isOverlapping(pair.first, pair.second) {
-			total += 1
-		} else if isOverlapping(pair.second, pair.first) {
+		if pair.first.Overlaps(pair.second) {
 			total += 1
 		}
 	}
@@ -60,7 +83,56 @@ func countOverlapTotal(pairs []Pair[SectionAssignment, SectionAssignment]) int {
 	return total
 }
 
+func countContainmentTotal(pairs []Pair[SectionAssignment, SectionAssignment]) int {
+	total := 0
+	for _, pair := range pairs {
+		if pair.first.ContainsInterval(pair.second) || pair.second.ContainsInterval(pair.first) {
+			total += 1
+		}
+	}
+	return total
+}
+
+// sections are whole numbers so touching assignments are adjacent
+// without sharing a section
+func classifyPair(pair Pair[SectionAssignment, SectionAssignment]) PairRelation {
+	a, b := pair.first, pair.second
+	if a == b {
+		return IDENTICAL
+	} else if a.ContainsInterval(b) || b.ContainsInterval(a) {
+		return CONTAINING
+	} else if a.Overlaps(b) {
+		return OVERLAPPING
+	} else if a.Adjacent(b) {
+		return TOUCHING
+	}
+	return DISJOINT
+}
+
+func classifyPairs(pairs []Pair[SectionAssignment, SectionAssignment]) []PairRelation {
+	relations := make([]PairRelation, 0)
+	for _, pair := range pairs {
+		relations = append(relations, classifyPair(pair))
+	}
+	return relations
+}
+
 func main() {
+	list := flag.Bool("list", false, "list the relationship of every pair")
+	coverage := flag.Bool("coverage", false, "report camp-wide section coverage")
+	k := flag.Int("k", 2, "minimum number of elves for the coverage report")
+	spanFlag := flag.String("span", "", "sections to check for gaps, e.g. 1-99 (defaults to the whole camp)")
+	flag.Parse()
+
+	var span *SectionAssignment
+	if *spanFlag != "" {
+		parsedSpan, err := parseSpan(*spanFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		span = &parsedSpan
+	}
+
 	start := time.Now()
 	file, err := os.Open("input.txt")
 	if err != nil {
@@ -74,9 +146,24 @@ func main() {
 
 	scanner := bufio.NewScanner(file)
 	sectionAssignmentPairs := parseSectionAssignmentPairs(scanner)
-	subRangeCount := countOverlapTotal(sectionAssignmentPairs)
+	containedCount := countContainmentTotal(sectionAssignmentPairs)
+	overlapCount := countOverlapTotal(sectionAssignmentPairs)
+	relations := classifyPairs(sectionAssignmentPairs)
+	var coverageReport CoverageReport
+	if *coverage {
+		coverageReport = analyseCoverage(sectionAssignmentPairs, *k, span)
+	}
 
 	elapsed := time.Since(start)
-	fmt.Println(subRangeCount)
+	if *list {
+		for i, pair := range sectionAssignmentPairs {
+			fmt.Printf("%d: %d-%d,%d-%d %s\n", i+1, pair.first.Min, pair.first.Max, pair.second.Min, pair.second.Max, relations[i])
+		}
+	}
+	fmt.Println("Fully contained:", containedCount)
+	fmt.Println("Overlapping:", overlapCount)
+	if *coverage {
+		printCoverageReport(coverageReport, *k)
+	}
 	log.Printf("Time taken: %s", elapsed)
 }
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// where a stack's number sits in the footer, crates above it have to
+// overlap these columns
+type StackColumn struct {
+	number int
+	start  int
+	end    int
+}
+
+type CrateToken struct {
+	label string
+	start int
+	end   int
+}
+
+func isFooter(line string) bool {
+	return strings.TrimSpace(line) != "" && strings.Trim(line, " 0123456789") == ""
+}
+
+func parseFooter(line string) ([]StackColumn, error) {
+	columns := make([]StackColumn, 0)
+	for i := 0; i < len(line); i++ {
+		if line[i] == ' ' {
+			continue
+		}
+		end := i
+		for end+1 < len(line) && line[end+1] != ' ' {
+			end++
+		}
+		number, err := strconv.Atoi(line[i : end+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid stack number %q", line[i:end+1])
+		}
+		if number != len(columns)+1 {
+			return nil, fmt.Errorf("expected stack %d but found %d", len(columns)+1, number)
+		}
+		columns = append(columns, StackColumn{number: number, start: i, end: end})
+		i = end
+	}
+	return columns, nil
+}
+
+func parseCrateRow(line string) ([]CrateToken, error) {
+	tokens := make([]CrateToken, 0)
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case ' ':
+			continue
+		case '[':
+			end := strings.IndexByte(line[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed crate at column %d", i+1)
+			}
+			end += i
+			label := line[i+1 : end]
+			if label == "" || strings.ContainsAny(label, "[ ") {
+				return nil, fmt.Errorf("invalid crate label %q at column %d", label, i+1)
+			}
+			tokens = append(tokens, CrateToken{label: label, start: i, end: end})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected %q at column %d", line[i], i+1)
+		}
+	}
+	return tokens, nil
+}
+
+func findStackColumn(columns []StackColumn, token CrateToken) (int, error) {
+	found := -1
+	for i, column := range columns {
+		if token.start <= column.end && column.start <= token.end {
+			if found >= 0 {
+				return -1, fmt.Errorf("crate [%s] sits above stacks %d and %d", token.label, found+1, i+1)
+			}
+			found = i
+		}
+	}
+	if found < 0 {
+		return -1, fmt.Errorf("crate [%s] isn't above any stack number", token.label)
+	}
+	return found, nil
+}
+
+// lines are the diagram rows with the numbered footer last. The footer sets
+// the stack count and columns, so labels can be any width, stacks can go
+// past 9 and rows can stop short of the last stack.
+func parseDiagram(lines []string) ([]stack, error) {
+	if len(lines) == 0 || !isFooter(lines[len(lines)-1]) {
+		return nil, fmt.Errorf("line %d: diagram has no numbered footer", len(lines))
+	}
+	footer := len(lines) - 1
+	columns, err := parseFooter(lines[footer])
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", footer+1, err)
+	}
+	reversedStacks := make([]stack, len(columns))
+	for i, line := range lines[:footer] {
+		tokens, err := parseCrateRow(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		seen := make(map[int]struct{})
+		for _, token := range tokens {
+			column, err := findStackColumn(columns, token)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if _, found := seen[column]; found {
+				return nil, fmt.Errorf("line %d: two crates above stack %d", i+1, column+1)
+			}
+			seen[column] = struct{}{}
+			reversedStacks[column] = reversedStacks[column].Push(token.label)
+		}
+		// once a stack has a crate every row below needs one too
+		for column, reversedStack := range reversedStacks {
+			if _, found := seen[column]; !found && !reversedStack.isEmpty() {
+				return nil, fmt.Errorf("line %d: crate floating above an empty space in stack %d", i, column+1)
+			}
+		}
+	}
+	return reverseStacks(reversedStacks), nil
+}
+
+func centerIn(text string, width int) string {
+	left := (width - len(text)) / 2
+	return strings.Repeat(" ", left) + text + strings.Repeat(" ", width-len(text)-left)
+}
+
+// every stack gets the same cell width so rows line up, wide enough for the
+// longest label in brackets and the highest stack number
+func findCellWidth(stacks []stack) int {
+	width := len(strconv.Itoa(len(stacks)))
+	for _, stack := range stacks {
+		for _, label := range stack {
+			if len(label)+2 > width {
+				width = len(label) + 2
+			}
+		}
+	}
+	if width < 3 {
+		width = 3
+	}
+	return width
+}
+
+// renderDiagram draws the stacks the way the puzzle input does, crates and
+// numbers centred in their cells so parseDiagram reads it back unchanged
+func renderDiagram(stacks []stack) string {
+	cellWidth := findCellWidth(stacks)
+	height := 0
+	for _, stack := range stacks {
+		if len(stack) > height {
+			height = len(stack)
+		}
+	}
+	rows := make([]string, 0)
+	for level := height - 1; level >= 0; level-- {
+		cells := make([]string, 0)
+		for _, stack := range stacks {
+			cell := ""
+			if level < len(stack) {
+				cell = "[" + stack[level] + "]"
+			}
+			cells = append(cells, centerIn(cell, cellWidth))
+		}
+		rows = append(rows, strings.Join(cells, " "))
+	}
+	footer := make([]string, 0)
+	for i := range stacks {
+		footer = append(footer, centerIn(strconv.Itoa(i+1), cellWidth))
+	}
+	rows = append(rows, strings.Join(footer, " "))
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func sameStacks(a []stack, b []stack) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestDiagramRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		stacks []stack
+	}{
+		{"puzzle example", []stack{{"Z", "N"}, {"M", "C", "D"}, {"P"}}},
+		{"multi-character labels", []stack{{"AB", "C"}, {"DEF"}, {"G", "HI", "JKLM"}}},
+		{"more than 9 stacks", []stack{
+			{"A"}, {"B"}, {"C"}, {"D", "E"}, {"F"}, {"G"},
+			{"H"}, {"I"}, {"J", "K", "L"}, {"M"}, {"N"},
+		}},
+		{"empty stack in the middle", []stack{{"A", "B"}, {}, {"C"}}},
+		{"empty stack at the end", []stack{{"A"}, {"B", "C"}, {}}},
+		{"only empty stacks", []stack{{}, {}}},
+		{"wide labels past 9 stacks", []stack{
+			{"LONG"}, {}, {"X", "YY"}, {}, {}, {}, {}, {}, {}, {"Z"}, {"A", "B", "C"}, {},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diagram := renderDiagram(test.stacks)
+			parsed, err := parseDiagram(strings.Split(diagram, "\n"))
+			if err != nil {
+				t.Fatalf("parsing rendered diagram:\n%s\nfailed: %v", diagram, err)
+			}
+			if !sameStacks(parsed, test.stacks) {
+				t.Errorf("got %v, want %v from diagram:\n%s", parsed, test.stacks, diagram)
+			}
+		})
+	}
+}
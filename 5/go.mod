@@ -1,3 +1,3 @@
-module main
+module aoc2022/day5
 
 go 1.18
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// an applied instruction together with the crates it took off the origin
+// stack, bottom first as they sat there, which is all undo needs
+type MoveRecord struct {
+	instruction Instruction
+	crates      []string
+}
+
+// CranePlan steps a crane forwards and backwards through the instruction
+// list, the applied records always cover instructions[:len(applied)]
+type CranePlan struct {
+	crane        Crane
+	instructions []Instruction
+	initial      []stack
+	boxStacks    []stack
+	applied      []MoveRecord
+}
+
+func newCranePlan(boxStacks []stack, instructions []Instruction, crane Crane) *CranePlan {
+	return &CranePlan{
+		crane:        crane,
+		instructions: instructions,
+		initial:      copyStacks(boxStacks),
+		boxStacks:    copyStacks(boxStacks),
+		applied:      make([]MoveRecord, 0),
+	}
+}
+
+func (p *CranePlan) position() int {
+	return len(p.applied)
+}
+
+func (p *CranePlan) redo() error {
+	if p.position() == len(p.instructions) {
+		return errors.New("no more instructions to apply")
+	}
+	instruction := p.instructions[p.position()]
+	if err := checkInstruction(p.boxStacks, instruction); err != nil {
+		return &InstructionError{instruction: instruction, boxStacks: copyStacks(p.boxStacks), err: err}
+	}
+	origin := p.boxStacks[instruction.origin]
+	crates := append([]string{}, origin[len(origin)-instruction.quantity:]...)
+	boxStacks, err := p.crane.move(p.boxStacks, instruction)
+	if err != nil {
+		return &InstructionError{instruction: instruction, boxStacks: copyStacks(p.boxStacks), err: err}
+	}
+	p.boxStacks = boxStacks
+	p.applied = append(p.applied, MoveRecord{instruction: instruction, crates: crates})
+	return nil
+}
+
+// whichever crane made the move, the moved crates are the top of the
+// destination and go back onto the origin in their recorded order
+func (p *CranePlan) undo() error {
+	if p.position() == 0 {
+		return errors.New("no instructions to undo")
+	}
+	record := p.applied[len(p.applied)-1]
+	destination, _, err := p.boxStacks[record.instruction.destination].PopMultiple(len(record.crates))
+	if err != nil {
+		return err
+	}
+	p.boxStacks[record.instruction.destination] = destination
+	p.boxStacks[record.instruction.origin] = p.boxStacks[record.instruction.origin].PushMultiple(record.crates)
+	p.applied = p.applied[:len(p.applied)-1]
+	return nil
+}
+
+// jumpTo leaves the stacks as they are after the first k instructions
+func (p *CranePlan) jumpTo(k int) error {
+	if k < 0 || k > len(p.instructions) {
+		return fmt.Errorf("can't jump to instruction %d of %d", k, len(p.instructions))
+	}
+	for p.position() > k {
+		if err := p.undo(); err != nil {
+			return err
+		}
+	}
+	for p.position() < k {
+		if err := p.redo(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labels aren't unique, so this is the last applied move of any crate
+// with the label
+func (p *CranePlan) lastMoved(label string) (MoveRecord, bool) {
+	for i := len(p.applied) - 1; i >= 0; i-- {
+		for _, crate := range p.applied[i].crates {
+			if crate == label {
+				return p.applied[i], true
+			}
+		}
+	}
+	return MoveRecord{}, false
+}
+
+// replays stack heights from the start, only looking at applied moves
+func (p *CranePlan) firstEmptied(stackIndex int) (MoveRecord, bool) {
+	heights := make([]int, len(p.initial))
+	for i, boxStack := range p.initial {
+		heights[i] = len(boxStack)
+	}
+	for _, record := range p.applied {
+		heights[record.instruction.origin] -= len(record.crates)
+		heights[record.instruction.destination] += len(record.crates)
+		if record.instruction.origin == stackIndex && len(record.crates) > 0 && heights[stackIndex] == 0 {
+			return record, true
+		}
+	}
+	return MoveRecord{}, false
+}
+
+func (p *CranePlan) describePosition() string {
+	description := fmt.Sprintf("at instruction %d of %d", p.position(), len(p.instructions))
+	if p.position() > 0 {
+		last := p.applied[len(p.applied)-1].instruction
+		description += fmt.Sprintf(", last %s (line %d)", last, last.line)
+	}
+	return description
+}
+
+func describeRecord(record MoveRecord) string {
+	return fmt.Sprintf("%s (line %d) moved %s", record.instruction, record.instruction.line, strings.Join(record.crates, " "))
+}
+
+const EXPLORE_HELP = `commands:
+  next, redo         apply the next instruction
+  undo               undo the last instruction
+  jump K             go to the state after K instructions
+  last X             when did a crate labelled X last move
+  emptied N          which instruction first emptied stack N
+  show               print the crate diagram
+  top                print the top crates
+  quit`
+
+func exploreCranePlan(plan *CranePlan, input io.Reader, output io.Writer) {
+	scanner := bufio.NewScanner(input)
+	fmt.Fprintln(output, EXPLORE_HELP)
+	fmt.Fprintf(output, "%s\n> ", plan.describePosition())
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			fmt.Fprint(output, "> ")
+			continue
+		}
+		var err error
+		switch {
+		case fields[0] == "next" || fields[0] == "redo":
+			err = plan.redo()
+		case fields[0] == "undo":
+			err = plan.undo()
+		case fields[0] == "jump" && len(fields) == 2:
+			var k int
+			if k, err = strconv.Atoi(fields[1]); err == nil {
+				err = plan.jumpTo(k)
+			}
+		case fields[0] == "last" && len(fields) == 2:
+			if record, found := plan.lastMoved(fields[1]); found {
+				fmt.Fprintln(output, describeRecord(record))
+			} else {
+				fmt.Fprintf(output, "no crate %s has moved yet\n", fields[1])
+			}
+		case fields[0] == "emptied" && len(fields) == 2:
+			var n int
+			if n, err = strconv.Atoi(fields[1]); err == nil {
+				if record, found := plan.firstEmptied(n - 1); found {
+					fmt.Fprintln(output, describeRecord(record))
+				} else {
+					fmt.Fprintf(output, "stack %d hasn't been emptied yet\n", n)
+				}
+			}
+		case fields[0] == "show":
+			fmt.Fprintln(output, renderDiagram(plan.boxStacks))
+		case fields[0] == "top":
+			fmt.Fprintln(output, readTopBoxes(plan.boxStacks))
+		case fields[0] == "quit":
+			return
+		default:
+			err = errors.New(EXPLORE_HELP)
+		}
+		if err != nil {
+			fmt.Fprintln(output, err)
+		}
+		fmt.Fprintf(output, "%s\n> ", plan.describePosition())
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestUndoRestoresStacks(t *testing.T) {
+	initial := []stack{{"A", "B", "C"}, {"D"}}
+	instructions := []Instruction{
+		{quantity: 2, origin: 0, destination: 0, line: 1},
+		{quantity: 2, origin: 0, destination: 1, line: 2},
+		{quantity: 1, origin: 1, destination: 1, line: 3},
+		{quantity: 3, origin: 1, destination: 0, line: 4},
+	}
+	for _, crane := range createCranes() {
+		plan := newCranePlan(initial, instructions, crane)
+		states := [][]stack{copyStacks(plan.boxStacks)}
+		for range instructions {
+			if err := plan.redo(); err != nil {
+				t.Fatalf("CrateMover %s: %v", crane.model, err)
+			}
+			states = append(states, copyStacks(plan.boxStacks))
+		}
+		for k := len(instructions) - 1; k >= 0; k-- {
+			if err := plan.undo(); err != nil {
+				t.Fatalf("CrateMover %s: %v", crane.model, err)
+			}
+			if !sameStacks(plan.boxStacks, states[k]) {
+				t.Errorf("CrateMover %s: undoing to instruction %d gave %v, want %v", crane.model, k, plan.boxStacks, states[k])
+			}
+		}
+		if !sameStacks(plan.boxStacks, initial) {
+			t.Errorf("CrateMover %s: undoing everything gave %v, want %v", crane.model, plan.boxStacks, initial)
+		}
+		if err := plan.jumpTo(1); err != nil {
+			t.Fatalf("CrateMover %s: %v", crane.model, err)
+		}
+		if !sameStacks(plan.boxStacks, initial) {
+			t.Errorf("CrateMover %s: a same-stack move changed the stacks to %v", crane.model, plan.boxStacks)
+		}
+	}
+}
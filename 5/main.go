@@ -2,25 +2,55 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"strings"
 	"time"
-	"unicode"
 )
 
-const BOX_WIDTH = 4
+const EMPTY_STACK_TOP = "_"
+
+var errEmptyStack = errors.New("stack is empty")
 
 type Instruction struct {
 	quantity    int
 	origin      int
 	destination int
+	line        int
+}
+
+// the first instruction that can't be carried out, with the stacks as they
+// were just before it
+type InstructionError struct {
+	instruction Instruction
+	boxStacks   []stack
+	err         error
+}
+
+func (e *InstructionError) Error() string {
+	return fmt.Sprintf("line %d: %s: %s\n%s", e.instruction.line, e.instruction, e.err, describeStacks(e.boxStacks))
+}
+
+func (e *InstructionError) Unwrap() error {
+	return e.err
 }
 
 type stack []string
 
+// a crane model decides how a single instruction moves crates
+type Crane struct {
+	model string
+	move  func(boxStacks []stack, instruction Instruction) ([]stack, error)
+}
+
+func (instruction Instruction) String() string {
+	return fmt.Sprintf("move %d from %d to %d", instruction.quantity, instruction.origin+1, instruction.destination+1)
+}
+
 func (s stack) Push(v string) stack {
 	return append(s, v)
 }
@@ -29,15 +59,27 @@ func (s stack) PushMultiple(v []string) stack {
 	return append(s, v...)
 }
 
-// don't care if stack is empty
-func (s stack) Pop() (stack, string) {
+func (s stack) Pop() (stack, string, error) {
 	length := len(s)
-	return s[:length-1], s[length-1]
+	if length == 0 {
+		return s, "", errEmptyStack
+	}
+	return s[:length-1], s[length-1], nil
 }
 
-func (s stack) PopMultiple(quantity int) (stack, []string) {
+func (s stack) PopMultiple(quantity int) (stack, []string, error) {
 	length := len(s)
-	return s[:length-quantity], s[length-quantity:]
+	if quantity < 0 || quantity > length {
+		return s, nil, fmt.Errorf("can't take %d crates from a stack of %d", quantity, length)
+	}
+	return s[:length-quantity], s[length-quantity:], nil
+}
+
+func (s stack) Peek() (string, error) {
+	if s.isEmpty() {
+		return "", errEmptyStack
+	}
+	return s[len(s)-1], nil
 }
 
 func (s stack) isEmpty() bool {
@@ -48,7 +90,7 @@ func reverseStacks(stacks []stack) []stack {
 	reversedStacks := make([]stack, len(stacks))
 	for i, stack := range stacks {
 		for !stack.isEmpty() {
-			newStack, value := stack.Pop()
+			newStack, value, _ := stack.Pop()
 			stack = newStack
 			reversedStacks[i] = reversedStacks[i].Push(value)
 		}
@@ -56,57 +98,208 @@ func reverseStacks(stacks []stack) []stack {
 	return reversedStacks
 }
 
-func parseBoxStacks(scanner *bufio.Scanner) ([]stack, []Instruction) {
-	scanner.Scan()
-	line := scanner.Text()
-	numOfStacks := ((len(line) - 3) / BOX_WIDTH) + 1
-	reversedStacks := make([]stack, numOfStacks)
-	for line != "" {
-		for i := range reversedStacks {
-			value := string(line[i*BOX_WIDTH+1])
-			if value != " " && !unicode.IsNumber([]rune(value)[0]) {
-				reversedStacks[i] = reversedStacks[i].Push(value)
-			}
+func parseInstruction(line string, lineNumber int) (Instruction, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 6 || fields[0] != "move" || fields[2] != "from" || fields[4] != "to" {
+		return Instruction{}, fmt.Errorf("line %d: expected \"move N from A to B\", got %q", lineNumber, line)
+	}
+	values := make([]int, 0)
+	for _, field := range []string{fields[1], fields[3], fields[5]} {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return Instruction{}, fmt.Errorf("line %d: invalid number %q", lineNumber, field)
 		}
-		scanner.Scan()
-		line = scanner.Text()
+		values = append(values, value)
+	}
+	return Instruction{
+		quantity:    values[0],
+		origin:      values[1] - 1,
+		destination: values[2] - 1,
+		line:        lineNumber,
+	}, nil
+}
+
+func parseBoxStacks(scanner *bufio.Scanner) ([]stack, []Instruction, error) {
+	diagram := make([]string, 0)
+	for scanner.Scan() && scanner.Text() != "" {
+		diagram = append(diagram, scanner.Text())
 	}
+	boxStacks, err := parseDiagram(diagram)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the diagram and the blank line after it
+	lineNumber := len(diagram) + 1
 	instructions := make([]Instruction, 0)
 	for scanner.Scan() {
-		line = scanner.Text()
-		trimmedInstruction := strings.TrimPrefix(line, "move ")
-		trimmedInstruction = strings.ReplaceAll(trimmedInstruction, " from ", ",")
-		trimmedInstruction = strings.ReplaceAll(trimmedInstruction, " to ", ",")
-		splitInstruction := strings.Split(trimmedInstruction, ",")
-		quantity, _ := strconv.Atoi(splitInstruction[0])
-		origin, _ := strconv.Atoi(splitInstruction[1])
-		destination, _ := strconv.Atoi(splitInstruction[2])
-		instructions = append(instructions, Instruction{quantity: quantity, origin: origin - 1, destination: destination - 1})
-	}
-	boxStacks := reverseStacks(reversedStacks)
-	return boxStacks, instructions
-}
-
-func performInstructions(boxStacks []stack, instructions []Instruction) []stack {
-	for _, instruction := range instructions {
-		poppedStack, value := boxStacks[instruction.origin].PopMultiple(instruction.quantity)
-		pushedStack := boxStacks[instruction.destination].PushMultiple(value)
+		lineNumber++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		instruction, err := parseInstruction(line, lineNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		instructions = append(instructions, instruction)
+	}
+	return boxStacks, instructions, nil
+}
+
+func stackHeights(boxStacks []stack) []int {
+	heights := make([]int, len(boxStacks))
+	for i, boxStack := range boxStacks {
+		heights[i] = len(boxStack)
+	}
+	return heights
+}
+
+// whether an instruction can be carried out only depends on the stack heights
+func checkInstructionHeights(heights []int, instruction Instruction) error {
+	if instruction.origin < 0 || instruction.origin >= len(heights) {
+		return fmt.Errorf("no stack %d", instruction.origin+1)
+	}
+	if instruction.destination < 0 || instruction.destination >= len(heights) {
+		return fmt.Errorf("no stack %d", instruction.destination+1)
+	}
+	if instruction.quantity < 0 {
+		return fmt.Errorf("can't move %d crates", instruction.quantity)
+	}
+	if instruction.quantity > heights[instruction.origin] {
+		return fmt.Errorf("stack %d only has %d crates", instruction.origin+1, heights[instruction.origin])
+	}
+	return nil
+}
+
+func checkInstruction(boxStacks []stack, instruction Instruction) error {
+	return checkInstructionHeights(stackHeights(boxStacks), instruction)
+}
+
+// moves crates one at a time, so they land in reverse order
+func crateMover9000(boxStacks []stack, instruction Instruction) ([]stack, error) {
+	if err := checkInstruction(boxStacks, instruction); err != nil {
+		return boxStacks, err
+	}
+	for i := 0; i < instruction.quantity; i++ {
+		poppedStack, value, err := boxStacks[instruction.origin].Pop()
+		if err != nil {
+			return boxStacks, err
+		}
 		boxStacks[instruction.origin] = poppedStack
-		boxStacks[instruction.destination] = pushedStack
+		boxStacks[instruction.destination] = boxStacks[instruction.destination].Push(value)
+	}
+	return boxStacks, nil
+}
+
+// moves crates as a block, so they keep their order
+func crateMover9001(boxStacks []stack, instruction Instruction) ([]stack, error) {
+	if err := checkInstruction(boxStacks, instruction); err != nil {
+		return boxStacks, err
+	}
+	poppedStack, value, err := boxStacks[instruction.origin].PopMultiple(instruction.quantity)
+	if err != nil {
+		return boxStacks, err
+	}
+	// the destination is read after the pop so a move onto the same stack
+	// puts the crates back instead of duplicating them
+	boxStacks[instruction.origin] = poppedStack
+	boxStacks[instruction.destination] = boxStacks[instruction.destination].PushMultiple(value)
+	return boxStacks, nil
+}
+
+func createCranes() []Crane {
+	return []Crane{
+		{model: "9000", move: crateMover9000},
+		{model: "9001", move: crateMover9001},
+	}
+}
+
+func copyStacks(boxStacks []stack) []stack {
+	copiedStacks := make([]stack, len(boxStacks))
+	for i, boxStack := range boxStacks {
+		copiedStacks[i] = append(stack{}, boxStack...)
+	}
+	return copiedStacks
+}
+
+// works on a copy so the parsed stacks can be reused by another crane.
+// afterMove, if set, sees the stacks after every instruction.
+func performInstructions(
+	boxStacks []stack,
+	instructions []Instruction,
+	crane Crane,
+	afterMove func(step int, boxStacks []stack),
+) ([]stack, error) {
+	boxStacks = copyStacks(boxStacks)
+	for i, instruction := range instructions {
+		var err error
+		// cranes check the instruction before moving anything, so on error
+		// boxStacks is still the state before the failed move
+		boxStacks, err = crane.move(boxStacks, instruction)
+		if err != nil {
+			return boxStacks, &InstructionError{instruction: instruction, boxStacks: boxStacks, err: err}
+		}
+		if afterMove != nil {
+			afterMove(i+1, boxStacks)
+		}
+	}
+	return boxStacks, nil
+}
+
+func showDiagramEvery(every int, instructions []Instruction, crane Crane) func(step int, boxStacks []stack) {
+	if every <= 0 {
+		return nil
+	}
+	return func(step int, boxStacks []stack) {
+		if step%every == 0 || step == len(instructions) {
+			instruction := instructions[step-1]
+			fmt.Printf("CrateMover %s after %s (line %d):\n%s\n\n", crane.model, instruction, instruction.line, renderDiagram(boxStacks))
+		}
+	}
+}
+
+// stack heights change the same way whichever crane is used, so one pass
+// over the heights finds the first invalid move for both. The crates are
+// only moved to show the stacks in the error once one is found.
+func validateInstructions(boxStacks []stack, instructions []Instruction) error {
+	heights := stackHeights(boxStacks)
+	for i, instruction := range instructions {
+		if err := checkInstructionHeights(heights, instruction); err != nil {
+			before, _ := performInstructions(boxStacks, instructions[:i], Crane{model: "9001", move: crateMover9001}, nil)
+			return &InstructionError{instruction: instruction, boxStacks: before, err: err}
+		}
+		heights[instruction.origin] -= instruction.quantity
+		heights[instruction.destination] += instruction.quantity
 	}
-	return boxStacks
+	return nil
 }
 
 func readTopBoxes(stacks []stack) string {
 	top := ""
 	for _, stack := range stacks {
-		_, value := stack.Pop()
+		value, err := stack.Peek()
+		if err != nil {
+			value = EMPTY_STACK_TOP
+		}
 		top = top + value
 	}
 	return top
 }
 
+func describeStacks(stacks []stack) string {
+	description := make([]string, 0)
+	for i, stack := range stacks {
+		description = append(description, fmt.Sprintf("%d: %s", i+1, strings.Join(stack, " ")))
+	}
+	return strings.Join(description, "\n")
+}
+
 func main() {
+	craneModel := flag.String("crane", "both", "crane model to run: 9000, 9001 or both")
+	showEvery := flag.Int("show", 0, "print the crate diagram after every N instructions")
+	explore := flag.Bool("explore", false, "step through the instructions interactively with one crane")
+	flag.Parse()
+
 	start := time.Now()
 	file, err := os.Open("input.txt")
 	if err != nil {
@@ -119,11 +312,39 @@ func main() {
 	}()
 
 	scanner := bufio.NewScanner(file)
-	boxStacks, instructions := parseBoxStacks(scanner)
-	boxStacks = performInstructions(boxStacks, instructions)
-	topBoxes := readTopBoxes(boxStacks)
+	boxStacks, instructions, err := parseBoxStacks(scanner)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := validateInstructions(boxStacks, instructions); err != nil {
+		log.Fatal(err)
+	}
+	if *explore {
+		for _, crane := range createCranes() {
+			if *craneModel == crane.model {
+				exploreCranePlan(newCranePlan(boxStacks, instructions, crane), os.Stdin, os.Stdout)
+				return
+			}
+		}
+		log.Fatal("-explore needs -crane 9000 or -crane 9001")
+	}
+	topBoxes := make([]string, 0)
+	models := make([]string, 0)
+	for _, crane := range createCranes() {
+		if *craneModel == crane.model || *craneModel == "both" {
+			afterMove := showDiagramEvery(*showEvery, instructions, crane)
+			finalStacks, err := performInstructions(boxStacks, instructions, crane, afterMove)
+			if err != nil {
+				log.Fatal(err)
+			}
+			topBoxes = append(topBoxes, readTopBoxes(finalStacks))
+			models = append(models, crane.model)
+		}
+	}
 
 	elapsed := time.Since(start)
-	fmt.Println(topBoxes)
+	for i, model := range models {
+		fmt.Printf("CrateMover %s: %s\n", model, topBoxes[i])
+	}
 	log.Printf("Time taken: %s", elapsed)
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSameStackMoves(t *testing.T) {
+	for _, crane := range createCranes() {
+		for quantity := 0; quantity <= 3; quantity++ {
+			boxStacks := []stack{{"A", "B", "C"}, {"D"}}
+			instruction := Instruction{quantity: quantity, origin: 0, destination: 0, line: 1}
+			moved, err := performInstructions(boxStacks, []Instruction{instruction}, crane, nil)
+			if err != nil {
+				t.Fatalf("CrateMover %s, %s: %v", crane.model, instruction, err)
+			}
+			if !sameStacks(moved, boxStacks) {
+				t.Errorf("CrateMover %s, %s: got %v, want %v", crane.model, instruction, moved, boxStacks)
+			}
+		}
+	}
+}
+
+func TestCraneMoves(t *testing.T) {
+	expected := map[string][]stack{
+		"9000": {{"A"}, {"D", "C", "B"}},
+		"9001": {{"A"}, {"D", "B", "C"}},
+	}
+	for _, crane := range createCranes() {
+		boxStacks := []stack{{"A", "B", "C"}, {"D"}}
+		instruction := Instruction{quantity: 2, origin: 0, destination: 1, line: 1}
+		moved, err := performInstructions(boxStacks, []Instruction{instruction}, crane, nil)
+		if err != nil {
+			t.Fatalf("CrateMover %s: %v", crane.model, err)
+		}
+		if !sameStacks(moved, expected[crane.model]) {
+			t.Errorf("CrateMover %s: got %v, want %v", crane.model, moved, expected[crane.model])
+		}
+	}
+}
+
+func TestValidateInstructions(t *testing.T) {
+	boxStacks := []stack{{"A", "B", "C"}, {"D"}}
+	tests := []struct {
+		name         string
+		instructions []Instruction
+		invalidLine  int
+	}{
+		{"valid", []Instruction{{2, 0, 1, 1}, {3, 1, 0, 2}}, 0},
+		{"same stack then too many", []Instruction{{2, 0, 0, 1}, {4, 0, 1, 2}}, 2},
+		{"same stack then all", []Instruction{{2, 0, 0, 1}, {3, 0, 1, 2}}, 0},
+		{"no such stack", []Instruction{{1, 0, 1, 1}, {1, 2, 0, 2}}, 2},
+		{"emptied stack", []Instruction{{1, 1, 0, 1}, {1, 1, 0, 2}}, 2},
+	}
+	for _, test := range tests {
+		err := validateInstructions(boxStacks, test.instructions)
+		var instructionError *InstructionError
+		if test.invalidLine == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", test.name, err)
+			}
+		} else if !errors.As(err, &instructionError) {
+			t.Errorf("%s: expected an instruction error, got %v", test.name, err)
+		} else if instructionError.instruction.line != test.invalidLine {
+			t.Errorf("%s: got an error on line %d, want line %d", test.name, instructionError.instruction.line, test.invalidLine)
+		}
+	}
+	if !sameStacks(boxStacks, []stack{{"A", "B", "C"}, {"D"}}) {
+		t.Errorf("validation changed the stacks to %v", boxStacks)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type SegmentKind int
+
+const (
+	PREAMBLE SegmentKind = iota
+	PACKET
+	MESSAGE
+)
+
+func (k SegmentKind) String() string {
+	return [...]string{"preamble", "packet", "message"}[k]
+}
+
+// the data between two markers. offset is where the content starts, zero
+// based, and terminated is false when the marker that should end it never
+// turned up
+type Segment struct {
+	kind       SegmentKind
+	offset     int
+	content    string
+	terminated bool
+}
+
+// decodeSignal splits a signal into segments: anything before the first
+// start-of-packet marker, then packets that run until the next
+// start-of-message marker and messages that run until the next
+// start-of-packet marker
+func decodeSignal(signal string, packetMarkerLength int, messageMarkerLength int) []Segment {
+	segments := make([]Segment, 0)
+	kind := PREAMBLE
+	cursor := 0
+	for {
+		markerLength := packetMarkerLength
+		next := PACKET
+		if kind == PACKET {
+			markerLength = messageMarkerLength
+			next = MESSAGE
+		}
+		markerEnd := findPacketStart(signal[cursor:], markerLength)
+		if markerEnd < 0 {
+			segments = append(segments, Segment{kind: kind, offset: cursor, content: signal[cursor:], terminated: false})
+			return segments
+		}
+		markerStart := cursor + markerEnd - markerLength
+		segments = append(segments, Segment{kind: kind, offset: cursor, content: signal[cursor:markerStart], terminated: true})
+		cursor += markerEnd
+		kind = next
+	}
+}
+
+func describeSegment(segment Segment) string {
+	description := fmt.Sprintf("%s at %d, length %d: %q", segment.kind, segment.offset, len(segment.content), segment.content)
+	if !segment.terminated {
+		description += " (no marker found after it)"
+	}
+	return description
+}
+
+func reportDecodedSignals(reader io.Reader, packetMarkerLength int, messageMarkerLength int, output io.Writer) error {
+	bufferedReader := bufio.NewReader(reader)
+	for signalNumber := 1; ; signalNumber++ {
+		line, err := bufferedReader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		signal := strings.TrimRight(line, "\r\n")
+		if signal != "" {
+			fmt.Fprintf(output, "signal %d:\n", signalNumber)
+			for _, segment := range decodeSignal(signal, packetMarkerLength, messageMarkerLength) {
+				fmt.Fprintf(output, "  %s\n", describeSegment(segment))
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
@@ -2,47 +2,100 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 	"time"
 )
 
-const BUFFER_OFFSET = 14
+const START_OF_PACKET_LENGTH = 4
+const START_OF_MESSAGE_LENGTH = 14
 
-func parseSignal(scanner *bufio.Scanner) string {
-	scanner.Scan()
-	line := scanner.Text()
-	return line
+// a sliding window over the last few bytes of the signal that knows in O(1)
+// whether they're all different, by counting each byte value and how many
+// values appear more than once
+type MarkerWindow struct {
+	buffer     []byte
+	counts     [256]int
+	duplicates int
+	seen       int
 }
 
-func isPacketStart(bufferMap map[rune]int) bool {
-	packetStart := true
-	for _, count := range bufferMap {
-		packetStart = packetStart && count <= 1
+func newMarkerWindow(length int) *MarkerWindow {
+	return &MarkerWindow{buffer: make([]byte, length)}
+}
+
+// push adds the next byte, dropping the oldest one once the window is full,
+// and reports whether the window is now a marker
+func (w *MarkerWindow) push(b byte) bool {
+	slot := w.seen % len(w.buffer)
+	if w.seen >= len(w.buffer) {
+		oldest := w.buffer[slot]
+		if w.counts[oldest] == 2 {
+			w.duplicates--
+		}
+		w.counts[oldest]--
 	}
-	return packetStart
+	w.buffer[slot] = b
+	w.counts[b]++
+	if w.counts[b] == 2 {
+		w.duplicates++
+	}
+	w.seen++
+	return w.seen >= len(w.buffer) && w.duplicates == 0
+}
+
+// reads the first line without bufio.Scanner's token size limit
+func parseSignal(reader *bufio.Reader) string {
+	line, _ := reader.ReadString('\n')
+	return strings.TrimRight(line, "\r\n")
 }
 
-func findPacketStart(signal string) int {
-	start := -1
-	bufferMap := make(map[rune]int)
-	for _, char := range signal[:BUFFER_OFFSET] {
-		bufferMap[char] += 1
+// returns the number of characters read up to the end of the first marker,
+// or -1 if the signal doesn't have one
+func findPacketStart(signal string, markerLength int) int {
+	if markerLength <= 0 {
+		return -1
 	}
-	for i, char := range signal[BUFFER_OFFSET:] {
-		if isPacketStart(bufferMap) {
-			start = i + BUFFER_OFFSET
-			break
+	window := newMarkerWindow(markerLength)
+	for i := 0; i < len(signal); i++ {
+		if window.push(signal[i]) {
+			return i + 1
 		}
-		bufferMap[char] += 1
-		bufferMap[rune(signal[i])] -= 1
 	}
-	return start
+	return -1
+}
+
+func runStream(reader io.Reader, markerLengths []int, listAll bool) {
+	output := bufio.NewWriter(os.Stdout)
+	if err := reportStreamMarkers(reader, markerLengths, listAll, output); err != nil {
+		log.Fatal(err)
+	}
+	if err := output.Flush(); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func main() {
+	stream := flag.Bool("stream", false, "report every marker in every line of the input")
+	listAll := flag.Bool("all", false, "with -stream, list each marker as well as the summaries")
+	generate := flag.Int64("generate", 0, "with -stream, read this many generated bytes instead of the input")
+	decode := flag.Bool("decode", false, "split every line of the input into packets and messages")
+	packetLength := flag.Int("packet", START_OF_PACKET_LENGTH, "start-of-packet marker length")
+	messageLength := flag.Int("message", START_OF_MESSAGE_LENGTH, "start-of-message marker length")
+	flag.Parse()
+
 	start := time.Now()
+	markerLengths := []int{*packetLength, *messageLength}
+	if *stream && *generate > 0 {
+		runStream(newGeneratedSignal(*generate, 1), markerLengths, *listAll)
+		log.Printf("Time taken: %s", time.Since(start))
+		return
+	}
+
 	file, err := os.Open("input.txt")
 	if err != nil {
 		log.Fatal(err)
@@ -53,11 +106,25 @@ func main() {
 		}
 	}()
 
-	scanner := bufio.NewScanner(file)
-	signal := parseSignal(scanner)
-	packetStart := findPacketStart(signal)
+	if *stream {
+		runStream(file, markerLengths, *listAll)
+		log.Printf("Time taken: %s", time.Since(start))
+		return
+	}
+	if *decode {
+		if err := reportDecodedSignals(file, *packetLength, *messageLength, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Time taken: %s", time.Since(start))
+		return
+	}
+
+	signal := parseSignal(bufio.NewReader(file))
+	packetStart := findPacketStart(signal, *packetLength)
+	messageStart := findPacketStart(signal, *messageLength)
 
 	elapsed := time.Since(start)
 	fmt.Println(packetStart)
+	fmt.Println(messageStart)
 	log.Printf("Time taken: %s", elapsed)
 }
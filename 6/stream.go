@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+)
+
+const GENERATED_LINE_LENGTH = 1 << 20
+
+type Marker struct {
+	signal int
+	// which of the requested marker lengths this is, since lengths can repeat
+	window int
+	length int
+	// characters read in this signal up to the end of the marker
+	offset int64
+}
+
+type SignalSummary struct {
+	first int64
+	last  int64
+	count int64
+}
+
+func (w *MarkerWindow) reset() {
+	w.counts = [256]int{}
+	w.duplicates = 0
+	w.seen = 0
+}
+
+// streamMarkers reads signals one per line, a byte at a time, and reports
+// every marker of each length as soon as its last byte arrives. Memory use
+// only depends on the marker lengths, not the size of the stream.
+func streamMarkers(
+	reader io.Reader,
+	markerLengths []int,
+	onMarker func(marker Marker),
+	onSignalEnd func(signal int, length int64),
+) error {
+	windows := make([]*MarkerWindow, 0)
+	for _, markerLength := range markerLengths {
+		if markerLength <= 0 {
+			return fmt.Errorf("marker length must be positive, got %d", markerLength)
+		}
+		windows = append(windows, newMarkerWindow(markerLength))
+	}
+	bufferedReader := bufio.NewReader(reader)
+	signal := 1
+	var offset int64
+	for {
+		b, err := bufferedReader.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if b == '\r' {
+			continue
+		}
+		if b == '\n' {
+			onSignalEnd(signal, offset)
+			for _, window := range windows {
+				window.reset()
+			}
+			signal++
+			offset = 0
+			continue
+		}
+		offset++
+		for i, window := range windows {
+			if window.push(b) {
+				onMarker(Marker{signal: signal, window: i, length: markerLengths[i], offset: offset})
+			}
+		}
+	}
+	if offset > 0 {
+		onSignalEnd(signal, offset)
+	}
+	return nil
+}
+
+// an endless-looking signal of random lowercase letters, split into lines
+// so it's also a batch of signals
+type GeneratedSignal struct {
+	rng       *rand.Rand
+	remaining int64
+	written   int64
+}
+
+func newGeneratedSignal(size int64, seed int64) *GeneratedSignal {
+	return &GeneratedSignal{rng: rand.New(rand.NewSource(seed)), remaining: size}
+}
+
+func (g *GeneratedSignal) Read(p []byte) (int, error) {
+	if g.remaining <= 0 {
+		return 0, io.EOF
+	}
+	n := len(p)
+	if int64(n) > g.remaining {
+		n = int(g.remaining)
+	}
+	for i := 0; i < n; i++ {
+		g.written++
+		if g.written%GENERATED_LINE_LENGTH == 0 {
+			p[i] = '\n'
+		} else {
+			p[i] = byte('a' + g.rng.Intn(26))
+		}
+	}
+	g.remaining -= int64(n)
+	return n, nil
+}
+
+// summarises each signal as it ends, optionally listing every marker, so
+// nothing grows with the stream
+func reportStreamMarkers(reader io.Reader, markerLengths []int, listAll bool, output io.Writer) error {
+	summaries := make([]SignalSummary, len(markerLengths))
+	onMarker := func(marker Marker) {
+		summary := &summaries[marker.window]
+		if summary.count == 0 {
+			summary.first = marker.offset
+		}
+		summary.last = marker.offset
+		summary.count++
+		if listAll {
+			fmt.Fprintf(output, "signal %d: %d character marker at %d\n", marker.signal, marker.length, marker.offset)
+		}
+	}
+	onSignalEnd := func(signal int, length int64) {
+		for i, markerLength := range markerLengths {
+			summary := summaries[i]
+			if summary.count == 0 {
+				fmt.Fprintf(output, "signal %d (%d characters): no %d character marker\n", signal, length, markerLength)
+			} else {
+				fmt.Fprintf(output, "signal %d (%d characters): %d character markers first at %d, last at %d, %d in total\n",
+					signal, length, markerLength, summary.first, summary.last, summary.count)
+			}
+			summaries[i] = SignalSummary{}
+		}
+	}
+	return streamMarkers(reader, markerLengths, onMarker, onSignalEnd)
+}
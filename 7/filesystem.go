@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type File struct {
+	name   string
+	size   int
+	parent *Dir
+}
+
+type Dir struct {
+	name   string
+	parent *Dir
+	dirs   map[string]*Dir
+	files  map[string]*File
+}
+
+func newDir(name string, parent *Dir) *Dir {
+	return &Dir{
+		name:   name,
+		parent: parent,
+		dirs:   make(map[string]*Dir),
+		files:  make(map[string]*File),
+	}
+}
+
+func (d *Dir) path() string {
+	if d.parent == nil {
+		return "/"
+	}
+	if d.parent.parent == nil {
+		return "/" + d.name
+	}
+	return d.parent.path() + "/" + d.name
+}
+
+func (d *Dir) size() int {
+	total := 0
+	for _, file := range d.files {
+		total += file.size
+	}
+	for _, dir := range d.dirs {
+		total += dir.size()
+	}
+	return total
+}
+
+func (d *Dir) sortedDirs() []*Dir {
+	dirs := make([]*Dir, 0, len(d.dirs))
+	for _, dir := range d.dirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].name < dirs[j].name
+	})
+	return dirs
+}
+
+func (d *Dir) sortedFiles() []*File {
+	files := make([]*File, 0, len(d.files))
+	for _, file := range d.files {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].name < files[j].name
+	})
+	return files
+}
+
+// a repeated ls lists the same entries again, so existing ones are kept
+// unless they contradict what was listed before
+func (d *Dir) addEntry(entry string) error {
+	entrySplit := strings.SplitN(entry, " ", 2)
+	if len(entrySplit) != 2 || entrySplit[1] == "" {
+		return fmt.Errorf("invalid ls entry %q", entry)
+	}
+	name := entrySplit[1]
+	if entrySplit[0] == "dir" {
+		if _, found := d.files[name]; found {
+			return fmt.Errorf("%s is already listed as a file", name)
+		}
+		if _, found := d.dirs[name]; !found {
+			d.dirs[name] = newDir(name, d)
+		}
+		return nil
+	}
+	size, err := strconv.Atoi(entrySplit[0])
+	if err != nil {
+		return fmt.Errorf("invalid file size %q", entrySplit[0])
+	}
+	if _, found := d.dirs[name]; found {
+		return fmt.Errorf("%s is already listed as a directory", name)
+	}
+	if file, found := d.files[name]; found && file.size != size {
+		return fmt.Errorf("%s was listed with size %d, now %d", name, file.size, size)
+	}
+	d.files[name] = &File{name: name, size: size, parent: d}
+	return nil
+}
+
+func changeDir(root *Dir, current *Dir, target string) (*Dir, error) {
+	switch target {
+	case "/":
+		return root, nil
+	case "..":
+		// like a shell, cd .. from the root stays there
+		if current.parent == nil {
+			return current, nil
+		}
+		return current.parent, nil
+	}
+	dir, found := current.dirs[target]
+	if !found {
+		return nil, fmt.Errorf("cd into unknown directory %s from %s", target, current.path())
+	}
+	return dir, nil
+}
+
+// buildFileSystem replays the transcript, only trusting what ls has shown,
+// so cd has to target a directory that's already been listed
+func buildFileSystem(commands []string) (*Dir, error) {
+	root := newDir("/", nil)
+	current := root
+	listing := false
+	for i, command := range commands {
+		var err error
+		if strings.HasPrefix(command, "$ cd ") {
+			listing = false
+			current, err = changeDir(root, current, strings.TrimPrefix(command, "$ cd "))
+		} else if command == "$ ls" {
+			listing = true
+		} else if strings.HasPrefix(command, "$") {
+			err = fmt.Errorf("unknown command %q", command)
+		} else if listing {
+			err = current.addEntry(command)
+		} else if command != "" {
+			err = fmt.Errorf("output %q without ls", command)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	return root, nil
+}
+
+func walkDirs(dir *Dir, visit func(dir *Dir)) {
+	visit(dir)
+	for _, child := range dir.sortedDirs() {
+		walkDirs(child, visit)
+	}
+}
@@ -1,3 +1,3 @@
-module main
+module aoc2022/day7
 
 go 1.18
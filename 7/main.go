@@ -2,11 +2,10 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"log"
-	"math"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -22,72 +21,39 @@ func parseCommands(scanner *bufio.Scanner) []string {
 	return commands
 }
 
-func isFile(command string) bool {
-	return !strings.HasPrefix(command, "$") && !strings.HasPrefix(command, "dir")
-}
-
-func isDirChange(command string) bool {
-	return strings.HasPrefix(command, "$ cd")
-}
-
-func isGoPreviousDir(command string) bool {
-	return command == "$ cd .."
-}
-
-func parseFileBytes(command string) int {
-	commandSplit := strings.Split(command, " ")
-	byteValue, _ := strconv.Atoi(commandSplit[0])
-	return byteValue
-}
+func main() {
+	tree := flag.Bool("tree", false, "print the reconstructed filesystem")
+	du := flag.Bool("du", false, "print directory sizes, largest first")
+	exportJSON := flag.Bool("json", false, "print the reconstructed filesystem as JSON")
+	maxDepth := flag.Int("depth", -1, "deepest level to show, the root is 0 (negative for no limit)")
+	minSize := flag.Int("min-size", 0, "hide entries smaller than this")
+	capacity := flag.Int("capacity", TOTAL_DISK_SPACE, "total disk space")
+	requiredFree := flag.Int("required", TARGET_SPACE, "free space needed for the update")
+	queryText := flag.String("query", "", "query to run over the directories, e.g. 'largest 5'")
+	transcriptDir := flag.String("transcript", "", "print a transcript exploring this directory instead of solving")
+	verifyDir := flag.String("verify", "", "check sizes parsed from a generated transcript of this directory against the disk")
+	flag.Parse()
 
-func countDirBytes(commands []string) map[string]int {
-	currentPath := ""
-	currentDirs := []string{"/"}
-	dirBytes := make(map[string]int)
-	for _, command := range commands[1:] {
-		if isGoPreviousDir(command) {
-			lastDirIndex := strings.LastIndex(currentPath, "/")
-			currentPath = currentPath[:lastDirIndex]
-			currentDirs = currentDirs[:len(currentDirs)-1]
-		} else if isDirChange(command) {
-			dir := strings.TrimPrefix(command, "$ cd ")
-			currentPath += "/" + dir
-			currentDirs = append(currentDirs, currentPath)
-		} else if isFile(command) {
-			bytes := parseFileBytes(command)
-			for _, dir := range currentDirs {
-				dirBytes[dir] += bytes
-			}
+	if *transcriptDir != "" {
+		transcript, err := generateTranscript(os.DirFS(*transcriptDir))
+		if err != nil {
+			log.Fatal(err)
 		}
+		fmt.Println(strings.Join(transcript, "\n"))
+		return
 	}
-	return dirBytes
-}
-
-func calculateTargetByteSum(dirByteCounts map[string]int) int {
-	total := 0
-	for _, byteCount := range dirByteCounts {
-		if byteCount <= 100000 {
-			total += byteCount
+	if *verifyDir != "" {
+		mismatches, err := verifyTranscript(os.DirFS(*verifyDir))
+		if err != nil {
+			log.Fatal(err)
 		}
-	}
-	return total
-}
-
-func findDirToDelete(dirByteCounts map[string]int) int {
-	remainingSpace := TOTAL_DISK_SPACE - dirByteCounts["/"]
-	targetSpace := TARGET_SPACE - remainingSpace
-	currentMin := math.MaxInt
-	bytesDeleted := 0
-	for _, bytes := range dirByteCounts {
-		if bytes >= targetSpace && bytes-targetSpace < currentMin {
-			currentMin = bytes - targetSpace
-			bytesDeleted = bytes
+		if len(mismatches) > 0 {
+			log.Fatalf("transcript sizes don't match the disk:\n%s", strings.Join(mismatches, "\n"))
 		}
+		fmt.Println("transcript sizes match the disk")
+		return
 	}
-	return bytesDeleted
-}
 
-func main() {
 	start := time.Now()
 	file, err := os.Open("input.txt")
 	if err != nil {
@@ -101,12 +67,46 @@ func main() {
 
 	scanner := bufio.NewScanner(file)
 	commands := parseCommands(scanner)
-	dirByteCounts := countDirBytes(commands)
-	targetSum := calculateTargetByteSum(dirByteCounts)
-	spaceToDelete := findDirToDelete(dirByteCounts)
+	root, err := buildFileSystem(commands)
+	if err != nil {
+		log.Fatal(err)
+	}
+	params := DiskParams{capacity: *capacity, requiredFree: *requiredFree}
+	targetSum, err := evaluateQuery(PART_1_QUERY, root, params)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spaceToDelete, err := evaluateQuery(PART_2_QUERY, root, params)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var queryResult QueryResult
+	if *queryText != "" {
+		queryResult, err = evaluateQuery(*queryText, root, params)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	elapsed := time.Since(start)
-	fmt.Println(targetSum)
-	fmt.Println(spaceToDelete)
+	options := RenderOptions{maxDepth: *maxDepth, minSize: *minSize}
+	if *tree {
+		fmt.Println(renderTree(root, options))
+	}
+	if *du {
+		fmt.Println(renderDiskUsage(root, options))
+	}
+	if *exportJSON {
+		exported, err := exportTreeJSON(root, options)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(exported)
+	}
+	if *queryText != "" {
+		fmt.Println(queryResult)
+	}
+	fmt.Println(targetSum.value())
+	fmt.Println(spaceToDelete.value())
 	log.Printf("Time taken: %s", elapsed)
 }
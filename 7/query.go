@@ -0,0 +1,351 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// the two puzzle parts as queries, see parseQuery for the language
+const PART_1_QUERY = "sum size <= 100000"
+const PART_2_QUERY = "choose size >= need"
+
+type DiskParams struct {
+	capacity     int
+	requiredFree int
+}
+
+type DirInfo struct {
+	dir   *Dir
+	path  string
+	name  string
+	size  int
+	depth int
+}
+
+type Query struct {
+	action    string
+	condition func(info DirInfo) bool
+	order     string
+	limit     int
+}
+
+type QueryResult struct {
+	action string
+	dirs   []DirInfo
+	total  int
+}
+
+type queryParser struct {
+	tokens    []string
+	pos       int
+	variables map[string]int
+}
+
+// values a query can compare against besides plain numbers
+func createQueryVariables(root *Dir, params DiskParams) map[string]int {
+	used := root.size()
+	free := params.capacity - used
+	need := params.requiredFree - free
+	if need < 0 {
+		need = 0
+	}
+	return map[string]int{
+		"capacity": params.capacity,
+		"used":     used,
+		"free":     free,
+		"need":     need,
+	}
+}
+
+func tokenizeQuery(text string) ([]string, error) {
+	tokens := make([]string, 0)
+	for i := 0; i < len(text); {
+		char := rune(text[i])
+		switch {
+		case unicode.IsSpace(char):
+			i++
+		case char == '(' || char == ')':
+			tokens = append(tokens, string(char))
+			i++
+		case char == '"':
+			end := strings.IndexByte(text[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i+1)
+			}
+			tokens = append(tokens, text[i:i+end+2])
+			i += end + 2
+		case strings.ContainsRune("<>=!~", char):
+			end := i + 1
+			for end < len(text) && strings.ContainsRune("<>=!~", rune(text[end])) {
+				end++
+			}
+			tokens = append(tokens, text[i:end])
+			i = end
+		case unicode.IsLetter(char) || unicode.IsDigit(char):
+			end := i + 1
+			for end < len(text) && (unicode.IsLetter(rune(text[end])) || unicode.IsDigit(rune(text[end]))) {
+				end++
+			}
+			tokens = append(tokens, text[i:end])
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", char, i+1)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *queryParser) parseNumber(token string) (int, error) {
+	if value, found := p.variables[token]; found {
+		return value, nil
+	}
+	value, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number, got %q", token)
+	}
+	return value, nil
+}
+
+func compareInts(op string, a int, b int) (bool, error) {
+	switch op {
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	case "=", "==":
+		return a == b, nil
+	case "!=":
+		return a != b, nil
+	}
+	return false, fmt.Errorf("can't compare numbers with %q", op)
+}
+
+func (p *queryParser) parseComparison() (func(info DirInfo) bool, error) {
+	field := p.next()
+	op := p.next()
+	value := p.next()
+	switch field {
+	case "size", "depth":
+		number, err := p.parseNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := compareInts(op, 0, 0); err != nil {
+			return nil, err
+		}
+		return func(info DirInfo) bool {
+			actual := info.size
+			if field == "depth" {
+				actual = info.depth
+			}
+			matches, _ := compareInts(op, actual, number)
+			return matches
+		}, nil
+	case "name", "path":
+		if len(value) < 2 || !strings.HasPrefix(value, "\"") {
+			return nil, fmt.Errorf("expected a quoted string after %s %s, got %q", field, op, value)
+		}
+		pattern := value[1 : len(value)-1]
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q", pattern)
+		}
+		get := func(info DirInfo) string {
+			if field == "name" {
+				return info.name
+			}
+			return info.path
+		}
+		switch op {
+		case "~":
+			return func(info DirInfo) bool {
+				matches, _ := path.Match(pattern, get(info))
+				return matches
+			}, nil
+		case "=", "==":
+			return func(info DirInfo) bool { return get(info) == pattern }, nil
+		case "!=":
+			return func(info DirInfo) bool { return get(info) != pattern }, nil
+		}
+		return nil, fmt.Errorf("can't compare %s with %q", field, op)
+	}
+	return nil, fmt.Errorf("unknown field %q", field)
+}
+
+func (p *queryParser) parseUnary() (func(info DirInfo) bool, error) {
+	switch p.peek() {
+	case "not":
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(info DirInfo) bool { return !inner(info) }, nil
+	case "(":
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *queryParser) parseAnd() (func(info DirInfo) bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "and" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		left = func(info DirInfo) bool { return a(info) && b(info) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseOr() (func(info DirInfo) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		left = func(info DirInfo) bool { return a(info) || b(info) }
+	}
+	return left, nil
+}
+
+// parseQuery reads
+//
+//	[list|sum|count|choose] [condition] [largest N|smallest N]
+//
+// where a condition compares size, depth, name or path with < <= > >= = !=
+// or ~ (a glob), combined with and, or, not and brackets. Numbers can also
+// be capacity, used, free or need. choose picks the smallest matching
+// directory.
+func parseQuery(text string, variables map[string]int) (Query, error) {
+	tokens, err := tokenizeQuery(text)
+	if err != nil {
+		return Query{}, err
+	}
+	parser := &queryParser{tokens: tokens, variables: variables}
+	query := Query{action: "list", condition: func(info DirInfo) bool { return true }, limit: -1}
+	switch parser.peek() {
+	case "list", "sum", "count", "choose":
+		query.action = parser.next()
+	}
+	if parser.peek() != "" && parser.peek() != "largest" && parser.peek() != "smallest" {
+		if query.condition, err = parser.parseOr(); err != nil {
+			return Query{}, err
+		}
+	}
+	if parser.peek() == "largest" || parser.peek() == "smallest" {
+		query.order = parser.next()
+		if query.limit, err = parser.parseNumber(parser.next()); err != nil {
+			return Query{}, err
+		}
+	}
+	if parser.peek() != "" {
+		return Query{}, fmt.Errorf("unexpected %q", parser.peek())
+	}
+	return query, nil
+}
+
+func collectDirInfo(root *Dir) []DirInfo {
+	infos := make([]DirInfo, 0)
+	walkDirs(root, func(dir *Dir) {
+		infos = append(infos, DirInfo{dir: dir, path: dir.path(), name: dir.name, size: dir.size(), depth: dirDepth(dir)})
+	})
+	return infos
+}
+
+func runQuery(query Query, root *Dir) QueryResult {
+	dirs := make([]DirInfo, 0)
+	for _, info := range collectDirInfo(root) {
+		if query.condition(info) {
+			dirs = append(dirs, info)
+		}
+	}
+	if query.order == "largest" {
+		sort.SliceStable(dirs, func(i, j int) bool { return dirs[i].size > dirs[j].size })
+	} else if query.order == "smallest" || query.action == "choose" {
+		sort.SliceStable(dirs, func(i, j int) bool { return dirs[i].size < dirs[j].size })
+	}
+	if query.action == "choose" && len(dirs) > 1 {
+		dirs = dirs[:1]
+	}
+	if query.limit >= 0 && query.limit < len(dirs) {
+		dirs = dirs[:query.limit]
+	}
+	total := 0
+	for _, info := range dirs {
+		total += info.size
+	}
+	return QueryResult{action: query.action, dirs: dirs, total: total}
+}
+
+// the number a query boils down to, for sum and choose that's the answer
+func (r QueryResult) value() int {
+	if r.action == "count" {
+		return len(r.dirs)
+	}
+	return r.total
+}
+
+func (r QueryResult) String() string {
+	switch r.action {
+	case "sum", "count":
+		return fmt.Sprint(r.value())
+	case "choose":
+		if len(r.dirs) == 0 {
+			return "no directory matches"
+		}
+	}
+	lines := make([]string, 0)
+	for _, info := range r.dirs {
+		lines = append(lines, fmt.Sprintf("%d\t%s", info.size, info.path))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func evaluateQuery(text string, root *Dir, params DiskParams) (QueryResult, error) {
+	query, err := parseQuery(text, createQueryVariables(root, params))
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("query %q: %w", text, err)
+	}
+	return runQuery(query, root), nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// limits what the renderers show, a negative maxDepth means no limit and the
+// root is at depth 0
+type RenderOptions struct {
+	maxDepth int
+	minSize  int
+}
+
+type JSONNode struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Size     int        `json:"size"`
+	Children []JSONNode `json:"children,omitempty"`
+}
+
+// dirs and files sorted together by name, like the puzzle's listing
+type TreeEntry struct {
+	name string
+	dir  *Dir
+	file *File
+}
+
+func (d *Dir) sortedEntries() []TreeEntry {
+	entries := make([]TreeEntry, 0, len(d.dirs)+len(d.files))
+	for _, dir := range d.dirs {
+		entries = append(entries, TreeEntry{name: dir.name, dir: dir})
+	}
+	for _, file := range d.files {
+		entries = append(entries, TreeEntry{name: file.name, file: file})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+	return entries
+}
+
+func (e TreeEntry) size() int {
+	if e.dir != nil {
+		return e.dir.size()
+	}
+	return e.file.size
+}
+
+func (o RenderOptions) shows(depth int, size int) bool {
+	return (o.maxDepth < 0 || depth <= o.maxDepth) && size >= o.minSize
+}
+
+func renderTreeEntries(dir *Dir, depth int, options RenderOptions, lines []string) []string {
+	for _, entry := range dir.sortedEntries() {
+		if !options.shows(depth, entry.size()) {
+			continue
+		}
+		indent := strings.Repeat("  ", depth)
+		if entry.dir != nil {
+			lines = append(lines, fmt.Sprintf("%s- %s (dir)", indent, entry.name))
+			lines = renderTreeEntries(entry.dir, depth+1, options, lines)
+		} else {
+			lines = append(lines, fmt.Sprintf("%s- %s (file, size=%d)", indent, entry.name, entry.file.size))
+		}
+	}
+	return lines
+}
+
+// renderTree draws the filesystem like the puzzle's example listing
+func renderTree(root *Dir, options RenderOptions) string {
+	lines := []string{"- / (dir)"}
+	return strings.Join(renderTreeEntries(root, 1, options, lines), "\n")
+}
+
+// humanSize rounds up like du -h, one decimal place below 10
+func humanSize(size int) string {
+	units := []string{"", "K", "M", "G", "T"}
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprint(size)
+	}
+	if value < 10 {
+		return fmt.Sprintf("%.1f%s", float64(int(value*10+0.999999))/10, units[unit])
+	}
+	return fmt.Sprintf("%d%s", int(value+0.999999), units[unit])
+}
+
+func dirDepth(dir *Dir) int {
+	depth := 0
+	for dir.parent != nil {
+		dir = dir.parent
+		depth++
+	}
+	return depth
+}
+
+// renderDiskUsage lists directory sizes largest first, like du -h | sort -rh
+func renderDiskUsage(root *Dir, options RenderOptions) string {
+	dirs := make([]*Dir, 0)
+	sizes := make(map[*Dir]int)
+	walkDirs(root, func(dir *Dir) {
+		size := dir.size()
+		if options.shows(dirDepth(dir), size) {
+			dirs = append(dirs, dir)
+			sizes[dir] = size
+		}
+	})
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return sizes[dirs[i]] > sizes[dirs[j]]
+	})
+	lines := make([]string, 0)
+	for _, dir := range dirs {
+		lines = append(lines, fmt.Sprintf("%s\t%s", humanSize(sizes[dir]), dir.path()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func dirToJSONNode(dir *Dir, depth int, options RenderOptions) JSONNode {
+	node := JSONNode{Name: dir.name, Type: "dir", Size: dir.size(), Children: make([]JSONNode, 0)}
+	for _, entry := range dir.sortedEntries() {
+		if !options.shows(depth+1, entry.size()) {
+			continue
+		}
+		if entry.dir != nil {
+			node.Children = append(node.Children, dirToJSONNode(entry.dir, depth+1, options))
+		} else {
+			node.Children = append(node.Children, JSONNode{Name: entry.name, Type: "file", Size: entry.file.size})
+		}
+	}
+	return node
+}
+
+func exportTreeJSON(root *Dir, options RenderOptions) (string, error) {
+	exported, err := json.MarshalIndent(dirToJSONNode(root, 0, options), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(exported), nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+func listDir(fsys fs.FS, dirPath string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	listing := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			listing = append(listing, "dir "+entry.Name())
+		} else if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			listing = append(listing, fmt.Sprintf("%d %s", info.Size(), entry.Name()))
+		}
+	}
+	return listing, nil
+}
+
+// generateTranscript walks fsys and writes the commands someone would type
+// to explore it, cd-ing back up with cd .. like the puzzle input. Anything
+// that isn't a regular file or directory is left out.
+func generateTranscript(fsys fs.FS) ([]string, error) {
+	transcript := []string{"$ cd /"}
+	current := make([]string, 0)
+	err := fs.WalkDir(fsys, ".", func(walkPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		target := make([]string, 0)
+		if walkPath != "." {
+			target = strings.Split(walkPath, "/")
+			// WalkDir goes depth first, so the parent is always somewhere
+			// on the current path
+			for len(current) > len(target)-1 {
+				transcript = append(transcript, "$ cd ..")
+				current = current[:len(current)-1]
+			}
+			transcript = append(transcript, "$ cd "+target[len(target)-1])
+		}
+		current = target
+		listing, err := listDir(fsys, walkPath)
+		if err != nil {
+			return err
+		}
+		transcript = append(transcript, "$ ls")
+		transcript = append(transcript, listing...)
+		return nil
+	})
+	return transcript, err
+}
+
+// sizes of every directory straight from fsys, keyed like Dir.path
+func measureDirSizes(fsys fs.FS) (map[string]int, error) {
+	sizes := map[string]int{"/": 0}
+	err := fs.WalkDir(fsys, ".", func(walkPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if walkPath != "." {
+				sizes["/"+walkPath] += 0
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		for dir := path.Dir(walkPath); dir != "."; dir = path.Dir(dir) {
+			sizes["/"+dir] += int(info.Size())
+		}
+		sizes["/"] += int(info.Size())
+		return nil
+	})
+	return sizes, err
+}
+
+// verifyTranscript generates a transcript for fsys, parses it back and
+// checks every directory size against the one measured on disk
+func verifyTranscript(fsys fs.FS) ([]string, error) {
+	transcript, err := generateTranscript(fsys)
+	if err != nil {
+		return nil, err
+	}
+	root, err := buildFileSystem(transcript)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := measureDirSizes(fsys)
+	if err != nil {
+		return nil, err
+	}
+	mismatches := make([]string, 0)
+	parsed := collectDirInfo(root)
+	for _, info := range parsed {
+		if size, found := expected[info.path]; !found {
+			mismatches = append(mismatches, fmt.Sprintf("%s isn't on disk", info.path))
+		} else if size != info.size {
+			mismatches = append(mismatches, fmt.Sprintf("%s is %d on disk but %d from the transcript", info.path, size, info.size))
+		}
+	}
+	if len(parsed) != len(expected) {
+		mismatches = append(mismatches, fmt.Sprintf("%d directories on disk but %d from the transcript", len(expected), len(parsed)))
+	}
+	return mismatches, nil
+}
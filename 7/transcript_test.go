@@ -0,0 +1,116 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func createTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"a.txt":                {Data: []byte("0123456789")},
+		"empty":                {Mode: fs.ModeDir},
+		"my docs/notes 1.txt":  {Data: []byte("notes")},
+		"my docs/deep/er/file": {Data: []byte("abc")},
+		"z":                    {Data: []byte("zzzzzzz")},
+	}
+}
+
+func TestGenerateTranscript(t *testing.T) {
+	transcript, err := generateTranscript(createTestFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"$ cd /",
+		"$ ls",
+		"10 a.txt",
+		"dir empty",
+		"dir my docs",
+		"7 z",
+		"$ cd empty",
+		"$ ls",
+		"$ cd ..",
+		"$ cd my docs",
+		"$ ls",
+		"dir deep",
+		"5 notes 1.txt",
+		"$ cd deep",
+		"$ ls",
+		"dir er",
+		"$ cd er",
+		"$ ls",
+		"3 file",
+	}
+	if strings.Join(transcript, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got transcript:\n%s\nwant:\n%s", strings.Join(transcript, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestVerifyTranscript(t *testing.T) {
+	mismatches, err := verifyTranscript(createTestFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) > 0 {
+		t.Errorf("unexpected mismatches:\n%s", strings.Join(mismatches, "\n"))
+	}
+
+	sizes, err := measureDirSizes(createTestFS())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{
+		"/":                25,
+		"/empty":           0,
+		"/my docs":         8,
+		"/my docs/deep":    3,
+		"/my docs/deep/er": 3,
+	}
+	for dirPath, size := range expected {
+		if sizes[dirPath] != size {
+			t.Errorf("%s: got size %d, want %d", dirPath, sizes[dirPath], size)
+		}
+	}
+	if len(sizes) != len(expected) {
+		t.Errorf("got %d directories, want %d", len(sizes), len(expected))
+	}
+}
+
+func TestVerifyTranscriptOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	for name, file := range createTestFS() {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if file.Mode.IsDir() {
+			if err := os.MkdirAll(filePath, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, file.Data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fsys := os.DirFS(dir)
+	mismatches, err := verifyTranscript(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) > 0 {
+		t.Errorf("unexpected mismatches:\n%s", strings.Join(mismatches, "\n"))
+	}
+	// the sizes verifyTranscript compared against have to come from the disk
+	sizes, err := measureDirSizes(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizes["/"] != 25 || sizes["/my docs"] != 8 || sizes["/empty"] != 0 {
+		t.Errorf("got on-disk sizes %v", sizes)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func generateForest(rows int, columns int, rng *rand.Rand) [][]int {
+	treeGrid := make([][]int, rows)
+	for i := range treeGrid {
+		treeGrid[i] = make([]int, columns)
+		for j := range treeGrid[i] {
+			treeGrid[i][j] = rng.Intn(10)
+		}
+	}
+	return treeGrid
+}
+
+// every tree is taller than all the trees above and to the left of it, so
+// those views run to the edge, the worst case for the per tree scans
+func generateSlopedForest(rows int, columns int) [][]int {
+	treeGrid := make([][]int, rows)
+	for i := range treeGrid {
+		treeGrid[i] = make([]int, columns)
+		for j := range treeGrid[i] {
+			treeGrid[i][j] = i*columns + j
+		}
+	}
+	return treeGrid
+}
+
+// the per tree directional scans, kept as the baseline for the monotonic
+// stacks
+
+func isViewBlocked(start int, nextTree int) bool {
+	return start <= nextTree
+}
+
+// each view stops at the first tree at least as tall, or the edge, so an
+// edge tree sees nothing in that direction
+func findLeftViewScore(tree Point, treeGrid [][]int) int {
+	view := 0
+	for y := tree.y - 1; y >= 0; y-- {
+		view++
+		if isViewBlocked(treeGrid[tree.x][tree.y], treeGrid[tree.x][y]) {
+			break
+		}
+	}
+	return view
+}
+
+func findRightViewScore(tree Point, treeGrid [][]int) int {
+	view := 0
+	for y := tree.y + 1; y < len(treeGrid[tree.x]); y++ {
+		view++
+		if isViewBlocked(treeGrid[tree.x][tree.y], treeGrid[tree.x][y]) {
+			break
+		}
+	}
+	return view
+}
+
+func findUpViewScore(tree Point, treeGrid [][]int) int {
+	view := 0
+	for x := tree.x - 1; x >= 0; x-- {
+		view++
+		if isViewBlocked(treeGrid[tree.x][tree.y], treeGrid[x][tree.y]) {
+			break
+		}
+	}
+	return view
+}
+
+func findDownViewScore(tree Point, treeGrid [][]int) int {
+	view := 0
+	for x := tree.x + 1; x < len(treeGrid); x++ {
+		view++
+		if isViewBlocked(treeGrid[tree.x][tree.y], treeGrid[x][tree.y]) {
+			break
+		}
+	}
+	return view
+}
+
+func findScenicScore(tree Point, treeGrid [][]int) int {
+	left := findLeftViewScore(tree, treeGrid)
+	right := findRightViewScore(tree, treeGrid)
+	up := findUpViewScore(tree, treeGrid)
+	down := findDownViewScore(tree, treeGrid)
+	return left * right * up * down
+}
+
+func findHighestScenicScore(treeGrid [][]int) int {
+	highestScore := 0
+	for i := range treeGrid {
+		for j := range treeGrid[i] {
+			score := findScenicScore(Point{x: i, y: j}, treeGrid)
+			if score > highestScore {
+				highestScore = score
+			}
+		}
+	}
+	return highestScore
+}
+
+func findHighestScenicScoreLinear(treeGrid [][]int) int {
+	return findHighestScore(computeScenicScores(findViewDistances(treeGrid)))
+}
+
+// runs find over random and sloped square forests of a few sizes. With digit
+// heights no view can be much longer than ten trees on average, so the
+// random forests don't show much, the sloped ones do.
+func benchmarkHighestScore(b *testing.B, find func([][]int) int) {
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []int{64, 256, 512} {
+		forests := []struct {
+			kind     string
+			treeGrid [][]int
+		}{
+			{"random", generateForest(size, size, rng)},
+			{"sloped", generateSlopedForest(size, size)},
+		}
+		for _, forest := range forests {
+			b.Run(fmt.Sprintf("%s-%dx%d", forest.kind, size, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					find(forest.treeGrid)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkDirectionalScans(b *testing.B) {
+	benchmarkHighestScore(b, findHighestScenicScore)
+}
+
+func BenchmarkMonotonicStacks(b *testing.B) {
+	benchmarkHighestScore(b, findHighestScenicScoreLinear)
+}
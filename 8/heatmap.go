@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// the best tree and what it can see, distances are left, right, up, down
+type BestTree struct {
+	tree      Point
+	score     int
+	distances [4]int
+}
+
+func findVisibilityMask(treeGrid [][]int, visibleTrees map[Point]Sides) [][]int {
+	mask := make([][]int, len(treeGrid))
+	for i := range treeGrid {
+		mask[i] = make([]int, len(treeGrid[i]))
+		for j := range treeGrid[i] {
+			if _, visible := visibleTrees[indexToPoint(i, j)]; visible {
+				mask[i][j] = 1
+			}
+		}
+	}
+	return mask
+}
+
+func findBestTree(distances ViewDistances, scores [][]int) BestTree {
+	best := BestTree{score: -1}
+	for i, row := range scores {
+		for j, score := range row {
+			if score > best.score {
+				best = BestTree{
+					tree:      indexToPoint(i, j),
+					score:     score,
+					distances: [4]int{distances.left[i][j], distances.right[i][j], distances.up[i][j], distances.down[i][j]},
+				}
+			}
+		}
+	}
+	return best
+}
+
+func describeBestTree(best BestTree, treeGrid [][]int) string {
+	return fmt.Sprintf("Best tree at x=%d, y=%d (height %d): left %d, right %d, up %d, down %d, score %d",
+		best.tree.x, best.tree.y, treeGrid[best.tree.x][best.tree.y],
+		best.distances[0], best.distances[1], best.distances[2], best.distances[3], best.score)
+}
+
+// renderVisibilityOverlay shows visible trees by height, hidden ones as .
+// and the best tree as *
+func renderVisibilityOverlay(treeGrid [][]int, mask [][]int, best BestTree) string {
+	lines := make([]string, 0)
+	for i, row := range treeGrid {
+		var line strings.Builder
+		for j, tree := range row {
+			if indexToPoint(i, j) == best.tree {
+				line.WriteByte('*')
+			} else if mask[i][j] == 1 {
+				line.WriteByte(byte('0' + tree))
+			} else {
+				line.WriteByte('.')
+			}
+		}
+		lines = append(lines, line.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toGrayImage scales values so the largest is white, each tree becomes a
+// scale by scale block of pixels
+func toGrayImage(values [][]int, scale int) *image.Gray {
+	rows, columns := gridSize(values)
+	highest := 0
+	for _, row := range values {
+		for _, value := range row {
+			if value > highest {
+				highest = value
+			}
+		}
+	}
+	img := image.NewGray(image.Rect(0, 0, columns*scale, rows*scale))
+	for i, row := range values {
+		for j, value := range row {
+			shade := uint8(0)
+			if highest > 0 {
+				shade = uint8(value * 255 / highest)
+			}
+			for dx := 0; dx < scale; dx++ {
+				for dy := 0; dy < scale; dy++ {
+					img.SetGray(j*scale+dx, i*scale+dy, color.Gray{Y: shade})
+				}
+			}
+		}
+	}
+	return img
+}
+
+func writePNG(path string, img *image.Gray) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	// don't leave a half written image behind
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
+
+// binary P5 greymap, the pixels are already one byte each
+func writePGM(path string, img *image.Gray) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	bounds := img.Bounds()
+	fmt.Fprintf(writer, "P5\n%d %d\n255\n", bounds.Dx(), bounds.Dy())
+	for y := 0; y < bounds.Dy(); y++ {
+		writer.Write(img.Pix[y*img.Stride : y*img.Stride+bounds.Dx()])
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
+
+// exportHeatmaps writes prefix-heights, prefix-visible and prefix-scenic
+// in the given format, png or pgm
+func exportHeatmaps(prefix string, format string, scale int, treeGrid [][]int, mask [][]int, scores [][]int) error {
+	write := writePNG
+	if format == "pgm" {
+		write = writePGM
+	} else if format != "png" {
+		return fmt.Errorf("unknown heatmap format %q", format)
+	}
+	heatmaps := []struct {
+		name   string
+		values [][]int
+	}{
+		{name: "heights", values: treeGrid},
+		{name: "visible", values: mask},
+		{name: "scenic", values: scores},
+	}
+	for _, heatmap := range heatmaps {
+		path := fmt.Sprintf("%s-%s.%s", prefix, heatmap.name, format)
+		if err := write(path, toGrayImage(heatmap.values, scale)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
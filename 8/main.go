@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -33,10 +34,28 @@ func indexToPoint(x int, y int) Point {
 	}
 }
 
+// every row has to be the same length, a forest with no trees is fine
+func checkRectangular(treeGrid [][]int) error {
+	for i, row := range treeGrid {
+		if len(row) != len(treeGrid[0]) {
+			return fmt.Errorf("row %d has %d trees, expected %d", i+1, len(row), len(treeGrid[0]))
+		}
+	}
+	return nil
+}
+
+func gridSize(treeGrid [][]int) (int, int) {
+	if len(treeGrid) == 0 {
+		return 0, 0
+	}
+	return len(treeGrid), len(treeGrid[0])
+}
+
 func transposeGrid(treeGrid [][]int) [][]int {
-	transposedGrid := make([][]int, len(treeGrid))
+	rows, columns := gridSize(treeGrid)
+	transposedGrid := make([][]int, columns)
 	for i := range transposedGrid {
-		transposedGrid[i] = make([]int, len(treeGrid))
+		transposedGrid[i] = make([]int, rows)
 	}
 	for i := range treeGrid {
 		for j := range treeGrid[i] {
@@ -46,138 +65,83 @@ func transposeGrid(treeGrid [][]int) [][]int {
 	return transposedGrid
 }
 
-func processRowRight(row []int, rowIndex int, visibleTrees map[Point]bool) map[Point]bool {
-	currentTallestValue := row[0]
-	for j, tree := range row[1 : len(row)-1] {
+// every tree in the line is checked, edges included, since an edge tree
+// can also be visible from the far side
+func processRowRight(row []int, rowIndex int, visibleTrees map[Point]Sides) map[Point]Sides {
+	currentTallestValue := -1
+	for j, tree := range row {
 		if tree > currentTallestValue {
-			visibleTrees[indexToPoint(rowIndex, j+1)] = true
+			visibleTrees[indexToPoint(rowIndex, j)] |= FROM_LEFT
 			currentTallestValue = tree
 		}
 	}
 	return visibleTrees
 }
 
-func processRowLeft(row []int, rowIndex int, visibleTrees map[Point]bool) map[Point]bool {
-	currentTallestValue := row[len(row)-1]
-	for j := len(row) - 2; j > 0; j-- {
+func processRowLeft(row []int, rowIndex int, visibleTrees map[Point]Sides) map[Point]Sides {
+	currentTallestValue := -1
+	for j := len(row) - 1; j >= 0; j-- {
 		if row[j] > currentTallestValue {
-			visibleTrees[indexToPoint(rowIndex, j)] = true
+			visibleTrees[indexToPoint(rowIndex, j)] |= FROM_RIGHT
 			currentTallestValue = row[j]
 		}
 	}
 	return visibleTrees
 }
 
-func processColumnDown(column []int, columnIndex int, visibleTrees map[Point]bool) map[Point]bool {
-	currentTallestValue := column[0]
-	for i, tree := range column[1 : len(column)-1] {
+func processColumnDown(column []int, columnIndex int, visibleTrees map[Point]Sides) map[Point]Sides {
+	currentTallestValue := -1
+	for i, tree := range column {
 		if tree > currentTallestValue {
-			visibleTrees[indexToPoint(i+1, columnIndex)] = true
+			visibleTrees[indexToPoint(i, columnIndex)] |= FROM_TOP
 			currentTallestValue = tree
 		}
 	}
 	return visibleTrees
 }
 
-func processColumnUp(column []int, columnIndex int, visibleTrees map[Point]bool) map[Point]bool {
-	currentTallestValue := column[len(column)-1]
-	for i := len(column) - 2; i > 0; i-- {
+func processColumnUp(column []int, columnIndex int, visibleTrees map[Point]Sides) map[Point]Sides {
+	currentTallestValue := -1
+	for i := len(column) - 1; i >= 0; i-- {
 		if column[i] > currentTallestValue {
-			visibleTrees[indexToPoint(i, columnIndex)] = true
+			visibleTrees[indexToPoint(i, columnIndex)] |= FROM_BOTTOM
 			currentTallestValue = column[i]
 		}
 	}
 	return visibleTrees
 }
 
-func findVisibleTrees(treeGrid [][]int) map[Point]bool {
-	visibleTrees := make(map[Point]bool)
-	for i, row := range treeGrid[1 : len(treeGrid)-1] {
-		visibleTrees = processRowRight(row, i+1, visibleTrees)
-		visibleTrees = processRowLeft(row, i+1, visibleTrees)
+// maps each visible tree to the sides it can be seen from, hidden trees
+// aren't in the map
+func findVisibleTrees(treeGrid [][]int) map[Point]Sides {
+	visibleTrees := make(map[Point]Sides)
+	for i, row := range treeGrid {
+		visibleTrees = processRowRight(row, i, visibleTrees)
+		visibleTrees = processRowLeft(row, i, visibleTrees)
 	}
-	transposedGrid := transposeGrid(treeGrid)
-	for j, column := range transposedGrid[1 : len(transposedGrid)-1] {
-		visibleTrees = processColumnDown(column, j+1, visibleTrees)
-		visibleTrees = processColumnUp(column, j+1, visibleTrees)
+	for j, column := range transposeGrid(treeGrid) {
+		visibleTrees = processColumnDown(column, j, visibleTrees)
+		visibleTrees = processColumnUp(column, j, visibleTrees)
 	}
 	return visibleTrees
 }
 
-func countVisibleTree(visibleTrees map[Point]bool, treeGrid [][]int) int {
-	return len(visibleTrees) + len(treeGrid)*2 + 2*(len(treeGrid)-2)
-}
-
-func isViewBlocked(start int, nextTree int) bool {
-	return start <= nextTree
+func countVisibleTree(visibleTrees map[Point]Sides) int {
+	return len(visibleTrees)
 }
 
-func findLeftViewScore(tree Point, treeGrid [][]int) int {
-	view := 0
-	for {
-		view++
-		if tree.y-view <= 0 || isViewBlocked(treeGrid[tree.x][tree.y], treeGrid[tree.x][tree.y-view]) {
-			break
-		}
-	}
-	return view
-}
-
-func findRightViewScore(tree Point, treeGrid [][]int) int {
-	view := 0
-	for {
-		view++
-		if tree.y+view >= len(treeGrid)-1 || isViewBlocked(treeGrid[tree.x][tree.y], treeGrid[tree.x][tree.y+view]) {
-			break
-		}
-	}
-	return view
-}
-
-func findUpViewScore(tree Point, treeGrid [][]int) int {
-	view := 0
-	for {
-		view++
-		if tree.x-view <= 0 || isViewBlocked(treeGrid[tree.x][tree.y], treeGrid[tree.x-view][tree.y]) {
-			break
-		}
-	}
-	return view
-}
-
-func findDownViewScore(tree Point, treeGrid [][]int) int {
-	view := 0
-	for {
-		view++
-		if tree.x+view >= len(treeGrid)-1 || isViewBlocked(treeGrid[tree.x][tree.y], treeGrid[tree.x+view][tree.y]) {
-			break
-		}
-	}
-	return view
-}
-
-func findScenicScore(tree Point, treeGrid [][]int) int {
-	left := findLeftViewScore(tree, treeGrid)
-	right := findRightViewScore(tree, treeGrid)
-	up := findUpViewScore(tree, treeGrid)
-	down := findDownViewScore(tree, treeGrid)
-	return left * right * up * down
-}
-
-func findHighestScenicScore(treeGrid [][]int) int {
-	highestScore := 0
-	for i := range treeGrid {
-		for j := range treeGrid[i] {
-			score := findScenicScore(Point{x: i, y: j}, treeGrid)
-			if score > highestScore {
-				highestScore = score
-			}
-		}
+func main() {
+	heatmapPrefix := flag.String("heatmap", "", "write height, visibility and scenic score heatmaps starting with this path")
+	heatmapFormat := flag.String("format", "png", "heatmap format, png or pgm")
+	heatmapScale := flag.Int("scale", 4, "heatmap pixels per tree")
+	overlay := flag.Bool("overlay", false, "print the forest with hidden trees as . and the best tree as *")
+	sides := flag.Bool("sides", false, "break visibility down by the sides trees are seen from")
+	at := flag.String("at", "", "which sides the tree at x,y is visible from")
+	flag.Parse()
+	if *heatmapScale <= 0 {
+		log.Fatalf("heatmap scale must be positive, got %d", *heatmapScale)
 	}
-	return highestScore
-}
 
-func main() {
 	start := time.Now()
 	file, err := os.Open("input.txt")
 	if err != nil {
@@ -191,12 +155,44 @@ func main() {
 
 	scanner := bufio.NewScanner(file)
 	treeGrid := parseCommands(scanner)
+	if err := checkRectangular(treeGrid); err != nil {
+		log.Fatal(err)
+	}
 	visibleTrees := findVisibleTrees(treeGrid)
-	totalVisible := countVisibleTree(visibleTrees, treeGrid)
-	highestScore := findHighestScenicScore(treeGrid)
+	totalVisible := countVisibleTree(visibleTrees)
+	distances := findViewDistances(treeGrid)
+	scores := computeScenicScores(distances)
+	highestScore := findHighestScore(scores)
+	mask := findVisibilityMask(treeGrid, visibleTrees)
+	bestTree := findBestTree(distances, scores)
+	if *heatmapPrefix != "" {
+		if err := exportHeatmaps(*heatmapPrefix, *heatmapFormat, *heatmapScale, treeGrid, mask, scores); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	elapsed := time.Since(start)
+	if *overlay {
+		fmt.Println(renderVisibilityOverlay(treeGrid, mask, bestTree))
+	}
 	fmt.Println(totalVisible)
 	fmt.Println(highestScore)
+	if len(treeGrid) > 0 {
+		fmt.Println(describeBestTree(bestTree, treeGrid))
+	}
+	if *sides {
+		fmt.Println(describeVisibilityStats(findVisibilityStats(treeGrid, visibleTrees)))
+	}
+	if *at != "" {
+		var tree Point
+		if _, err := fmt.Sscanf(*at, "%d,%d", &tree.x, &tree.y); err != nil {
+			log.Fatalf("invalid coordinate %q, expected x,y", *at)
+		}
+		treeSides, err := visibleSidesAt(treeGrid, visibleTrees, tree)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Tree at x=%d, y=%d: %s\n", tree.x, tree.y, treeSides)
+	}
 	log.Printf("Time taken: %s", elapsed)
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+var testForests = []struct {
+	name     string
+	treeGrid [][]int
+	visible  int
+}{
+	{"1x5", [][]int{{3, 1, 4, 1, 5}}, 5},
+	{"5x1", [][]int{{3}, {1}, {4}, {1}, {5}}, 5},
+	{"3x5", [][]int{
+		{3, 0, 3, 7, 3},
+		{2, 5, 5, 1, 2},
+		{6, 5, 3, 3, 2},
+	}, 14},
+	{"5x3", [][]int{
+		{3, 2, 6},
+		{0, 5, 5},
+		{3, 5, 3},
+		{7, 1, 3},
+		{3, 2, 2},
+	}, 14},
+	{"puzzle example", [][]int{
+		{3, 0, 3, 7, 3},
+		{2, 5, 5, 1, 2},
+		{6, 5, 3, 3, 2},
+		{3, 3, 5, 4, 9},
+		{3, 5, 3, 9, 0},
+	}, 21},
+}
+
+func TestCheckRectangular(t *testing.T) {
+	for _, forest := range testForests {
+		if err := checkRectangular(forest.treeGrid); err != nil {
+			t.Errorf("%s: %v", forest.name, err)
+		}
+	}
+	if err := checkRectangular([][]int{}); err != nil {
+		t.Errorf("empty forest: %v", err)
+	}
+	if err := checkRectangular([][]int{{1, 2, 3}, {1, 2}}); err == nil {
+		t.Errorf("ragged forest should be rejected")
+	}
+}
+
+func TestCountVisibleTrees(t *testing.T) {
+	for _, forest := range testForests {
+		if got := countVisibleTree(findVisibleTrees(forest.treeGrid)); got != forest.visible {
+			t.Errorf("%s: got %d visible trees, want %d", forest.name, got, forest.visible)
+		}
+	}
+}
+
+func TestViewScores(t *testing.T) {
+	treeGrid := testForests[2].treeGrid
+	tests := []struct {
+		tree                  Point
+		left, right, up, down int
+	}{
+		{Point{x: 1, y: 2}, 1, 2, 1, 1},
+		{Point{x: 1, y: 1}, 1, 1, 1, 1},
+		{Point{x: 0, y: 3}, 3, 1, 0, 2},
+		{Point{x: 2, y: 0}, 0, 4, 2, 0},
+		{Point{x: 1, y: 4}, 2, 0, 1, 1},
+	}
+	for _, test := range tests {
+		left := findLeftViewScore(test.tree, treeGrid)
+		right := findRightViewScore(test.tree, treeGrid)
+		up := findUpViewScore(test.tree, treeGrid)
+		down := findDownViewScore(test.tree, treeGrid)
+		if left != test.left || right != test.right || up != test.up || down != test.down {
+			t.Errorf("%v: got left %d, right %d, up %d, down %d, want %d, %d, %d, %d",
+				test.tree, left, right, up, down, test.left, test.right, test.up, test.down)
+		}
+	}
+}
+
+func TestHighestScenicScoresAgree(t *testing.T) {
+	forests := make(map[string][][]int)
+	for _, forest := range testForests {
+		forests[forest.name] = forest.treeGrid
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []Point{{1, 40}, {40, 1}, {7, 23}, {23, 7}, {30, 30}} {
+		forests[fmt.Sprintf("random %dx%d", size.x, size.y)] = generateForest(size.x, size.y, rng)
+	}
+	for name, treeGrid := range forests {
+		scans := findHighestScenicScore(treeGrid)
+		stacks := findHighestScenicScoreLinear(treeGrid)
+		if scans != stacks {
+			t.Errorf("%s: directional scans found %d, monotonic stacks found %d", name, scans, stacks)
+		}
+	}
+	if got := findHighestScenicScore(testForests[4].treeGrid); got != 8 {
+		t.Errorf("puzzle example: got highest scenic score %d, want 8", got)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+// how far a tree can see in each direction, indexed like the tree grid
+type ViewDistances struct {
+	left  [][]int
+	right [][]int
+	up    [][]int
+	down  [][]int
+}
+
+// lookBack finds how far each tree in a line sees towards the start of it.
+// The stack holds the trees that could still block a later tree, tallest at
+// the bottom, so each tree is pushed and popped at most once.
+func lookBack(line []int) []int {
+	distances := make([]int, len(line))
+	blockers := make([]int, 0)
+	for i, tree := range line {
+		for len(blockers) > 0 && line[blockers[len(blockers)-1]] < tree {
+			blockers = blockers[:len(blockers)-1]
+		}
+		if len(blockers) == 0 {
+			distances[i] = i
+		} else {
+			distances[i] = i - blockers[len(blockers)-1]
+		}
+		blockers = append(blockers, i)
+	}
+	return distances
+}
+
+// lookBack run from the other end of the line
+func lookForward(line []int) []int {
+	distances := make([]int, len(line))
+	blockers := make([]int, 0)
+	for i := len(line) - 1; i >= 0; i-- {
+		for len(blockers) > 0 && line[blockers[len(blockers)-1]] < line[i] {
+			blockers = blockers[:len(blockers)-1]
+		}
+		if len(blockers) == 0 {
+			distances[i] = len(line) - 1 - i
+		} else {
+			distances[i] = blockers[len(blockers)-1] - i
+		}
+		blockers = append(blockers, i)
+	}
+	return distances
+}
+
+func findViewDistances(treeGrid [][]int) ViewDistances {
+	distances := ViewDistances{}
+	for _, row := range treeGrid {
+		distances.left = append(distances.left, lookBack(row))
+		distances.right = append(distances.right, lookForward(row))
+	}
+	transposedUp := make([][]int, 0)
+	transposedDown := make([][]int, 0)
+	for _, column := range transposeGrid(treeGrid) {
+		transposedUp = append(transposedUp, lookBack(column))
+		transposedDown = append(transposedDown, lookForward(column))
+	}
+	distances.up = transposeGrid(transposedUp)
+	distances.down = transposeGrid(transposedDown)
+	return distances
+}
+
+func computeScenicScores(distances ViewDistances) [][]int {
+	scores := make([][]int, len(distances.left))
+	for i := range distances.left {
+		scores[i] = make([]int, len(distances.left[i]))
+		for j := range distances.left[i] {
+			scores[i][j] = distances.left[i][j] * distances.right[i][j] * distances.up[i][j] * distances.down[i][j]
+		}
+	}
+	return scores
+}
+
+func findHighestScore(scores [][]int) int {
+	highestScore := 0
+	for _, row := range scores {
+		for _, score := range row {
+			if score > highestScore {
+				highestScore = score
+			}
+		}
+	}
+	return highestScore
+}
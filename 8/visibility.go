@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// a bitmask of the sides of the forest a tree can be seen from
+type Sides uint8
+
+const (
+	FROM_LEFT Sides = 1 << iota
+	FROM_RIGHT
+	FROM_TOP
+	FROM_BOTTOM
+)
+
+var SIDE_NAMES = []string{"left", "right", "top", "bottom"}
+
+func (s Sides) count() int {
+	return bits.OnesCount8(uint8(s))
+}
+
+func (s Sides) String() string {
+	names := make([]string, 0)
+	for i, name := range SIDE_NAMES {
+		if s&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "hidden"
+	}
+	return "visible from the " + strings.Join(names, ", ")
+}
+
+type VisibilityStats struct {
+	// trees visible from exactly k sides, index 0 counts the hidden ones
+	bySideCount [5]int
+	// trees visible from each side, in SIDE_NAMES order
+	bySide [4]int
+}
+
+func findVisibilityStats(treeGrid [][]int, visibleTrees map[Point]Sides) VisibilityStats {
+	stats := VisibilityStats{}
+	rows, columns := gridSize(treeGrid)
+	stats.bySideCount[0] = rows*columns - len(visibleTrees)
+	for _, sides := range visibleTrees {
+		stats.bySideCount[sides.count()]++
+		for i := range SIDE_NAMES {
+			if sides&(1<<i) != 0 {
+				stats.bySide[i]++
+			}
+		}
+	}
+	return stats
+}
+
+func describeVisibilityStats(stats VisibilityStats) string {
+	lines := make([]string, 0)
+	for k, count := range stats.bySideCount {
+		lines = append(lines, fmt.Sprintf("Visible from exactly %d sides: %d", k, count))
+	}
+	for i, count := range stats.bySide {
+		lines = append(lines, fmt.Sprintf("Visible from the %s: %d", SIDE_NAMES[i], count))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func visibleSidesAt(treeGrid [][]int, visibleTrees map[Point]Sides, tree Point) (Sides, error) {
+	rows, columns := gridSize(treeGrid)
+	if tree.x < 0 || tree.x >= rows || tree.y < 0 || tree.y >= columns {
+		return 0, fmt.Errorf("x=%d, y=%d is outside the %dx%d forest", tree.x, tree.y, rows, columns)
+	}
+	return visibleTrees[tree], nil
+}
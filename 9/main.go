@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -10,7 +11,8 @@ import (
 	"time"
 )
 
-const ROPE_LENGTH = 10
+const SHORT_ROPE_LENGTH = 2
+const LONG_ROPE_LENGTH = 10
 
 type Move struct {
 	direction string
@@ -79,12 +81,7 @@ func moveHorizontally(head Point, tail Point) Point {
 	return tail
 }
 
-func moveKnot(
-	head Point,
-	tail Point,
-	traversedPoints map[Point]struct{},
-	isTail bool,
-) (Point, map[Point]struct{}) {
+func moveKnot(head Point, tail Point) Point {
 	if !nextTo(head, tail) {
 		if head.x != tail.x && head.y != tail.y {
 			tail = moveDiagonally(head, tail)
@@ -93,55 +90,92 @@ func moveKnot(
 		} else if head.x != tail.x && head.y == tail.y {
 			tail = moveHorizontally(head, tail)
 		}
-		if isTail {
-			traversedPoints[tail] = struct{}{}
-		}
 	}
-	return tail, traversedPoints
+	return tail
 }
 
-func moveRope(rope []Point, traversedPoints map[Point]struct{}) ([]Point, map[Point]struct{}) {
-	tailIndex := len(rope) - 1
-	for !nextTo(rope[0], rope[1]) {
-		for i := 1; i < len(rope); i++ {
-			rope[i], traversedPoints = moveKnot(rope[i-1], rope[i], traversedPoints, i == tailIndex)
-		}
+// each knot follows the one in front of it, which has already moved
+func moveRope(rope []Point) []Point {
+	for i := 1; i < len(rope); i++ {
+		rope[i] = moveKnot(rope[i-1], rope[i])
 	}
-
-	return rope, traversedPoints
+	return rope
 }
 
-func moveHead(head Point, move Move) Point {
-	switch move.direction {
+func stepHead(head Point, direction string) Point {
+	switch direction {
 	case "U":
-		head.y += move.distance
+		head.y++
 	case "D":
-		head.y -= move.distance
+		head.y--
 	case "R":
-		head.x += move.distance
+		head.x++
 	case "L":
-		head.x -= move.distance
+		head.x--
 	}
 	return head
 }
 
-func findTailTraversedPoints(moves []Move) map[Point]struct{} {
-	traversedPoints := make(map[Point]struct{})
-	rope := make([]Point, ROPE_LENGTH)
-	tail := len(rope) - 1
+// simulateRope moves the head one step at a time, calling afterStep (if
+// set) with the rope after every step, and returns the points each knot
+// has visited, the head first and the tail last
+func simulateRope(
+	moves []Move,
+	knotCount int,
+	afterStep func(moveIndex int, step int, rope []Point),
+) []map[Point]struct{} {
+	rope := make([]Point, knotCount)
+	traversedPoints := make([]map[Point]struct{}, knotCount)
 	for i := range rope {
 		rope[i] = Point{x: 0, y: 0}
+		traversedPoints[i] = map[Point]struct{}{rope[i]: {}}
 	}
-	traversedPoints[rope[tail]] = struct{}{}
-	for _, move := range moves {
-		rope[0] = moveHead(rope[0], move)
-		rope, traversedPoints = moveRope(rope, traversedPoints)
+	for moveIndex, move := range moves {
+		for step := 0; step < move.distance; step++ {
+			rope[0] = stepHead(rope[0], move.direction)
+			rope = moveRope(rope)
+			for i, knot := range rope {
+				traversedPoints[i][knot] = struct{}{}
+			}
+			if afterStep != nil {
+				afterStep(moveIndex, step, rope)
+			}
+		}
 	}
-
 	return traversedPoints
 }
 
+func parseKnotCounts(knotCountList string) ([]int, error) {
+	knotCounts := make([]int, 0)
+	for _, field := range strings.Split(knotCountList, ",") {
+		knotCount, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || knotCount < 1 {
+			return nil, fmt.Errorf("invalid knot count %q", field)
+		}
+		knotCounts = append(knotCounts, knotCount)
+	}
+	return knotCounts, nil
+}
+
 func main() {
+	knotCountList := flag.String("knots", fmt.Sprintf("%d,%d", SHORT_ROPE_LENGTH, LONG_ROPE_LENGTH), "comma separated rope lengths to simulate")
+	perKnot := flag.Bool("per-knot", false, "print how many points every knot visited, not just the tail")
+	render := flag.String("render", "", "draw the rope after every head \"step\" or every \"move\"")
+	onlyMoves := flag.String("only", "", "with -render, comma separated move numbers to draw")
+	flag.Parse()
+
+	knotCounts, err := parseKnotCounts(*knotCountList)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *render != "" && *render != "step" && *render != "move" {
+		log.Fatalf("unknown render mode %q, expected step or move", *render)
+	}
+	selectedMoves, err := parseMoveSelection(*onlyMoves)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	start := time.Now()
 	file, err := os.Open("input.txt")
 	if err != nil {
@@ -155,9 +189,25 @@ func main() {
 
 	scanner := bufio.NewScanner(file)
 	moves := parseMoves(scanner)
-	traversedPoints := findTailTraversedPoints(moves)
+	allTraversedPoints := make([][]map[Point]struct{}, 0)
+	for _, knotCount := range knotCounts {
+		allTraversedPoints = append(allTraversedPoints, simulateRope(moves, knotCount, nil))
+	}
 
 	elapsed := time.Since(start)
-	fmt.Println(len(traversedPoints))
+	if *render != "" {
+		for i, knotCount := range knotCounts {
+			renderRopeMotion(os.Stdout, moves, knotCount, allTraversedPoints[i], *render == "step", selectedMoves)
+		}
+	}
+	for i, knotCount := range knotCounts {
+		traversedPoints := allTraversedPoints[i]
+		fmt.Printf("%d knots: tail visited %d points\n", knotCount, len(traversedPoints[knotCount-1]))
+		if *perKnot {
+			for knot, points := range traversedPoints {
+				fmt.Printf("  knot %d visited %d points\n", knot, len(points))
+			}
+		}
+	}
 	log.Printf("Time taken: %s", elapsed)
 }
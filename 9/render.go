@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type Bounds struct {
+	minX int
+	maxX int
+	minY int
+	maxY int
+}
+
+// the box around everywhere any knot went, so every frame lines up
+func findBounds(traversedPoints []map[Point]struct{}) Bounds {
+	bounds := Bounds{}
+	for _, points := range traversedPoints {
+		for point := range points {
+			bounds.minX = min(bounds.minX, point.x)
+			bounds.maxX = max(bounds.maxX, point.x)
+			bounds.minY = min(bounds.minY, point.y)
+			bounds.maxY = max(bounds.maxY, point.y)
+		}
+	}
+	return bounds
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// H for the head, T for the tail of a two knot rope like the puzzle,
+// otherwise numbered, with letters once the digits run out
+func knotLabel(knot int, knotCount int) byte {
+	if knot == 0 {
+		return 'H'
+	}
+	if knotCount == 2 {
+		return 'T'
+	}
+	if knot <= 9 {
+		return byte('0' + knot)
+	}
+	if knot-10 < 26 {
+		return byte('a' + knot - 10)
+	}
+	return '*'
+}
+
+func emptyFrame(bounds Bounds) [][]byte {
+	frame := make([][]byte, bounds.maxY-bounds.minY+1)
+	for i := range frame {
+		frame[i] = []byte(strings.Repeat(".", bounds.maxX-bounds.minX+1))
+	}
+	return frame
+}
+
+// y goes up in the puzzle, so the top row is maxY
+func setCell(frame [][]byte, bounds Bounds, point Point, cell byte) {
+	frame[bounds.maxY-point.y][point.x-bounds.minX] = cell
+}
+
+func frameToString(frame [][]byte) string {
+	rows := make([]string, 0)
+	for _, row := range frame {
+		rows = append(rows, string(row))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// knots in front cover the ones behind them, and the start shows as s when
+// nothing covers it
+func renderRope(rope []Point, bounds Bounds) string {
+	frame := emptyFrame(bounds)
+	setCell(frame, bounds, Point{x: 0, y: 0}, 's')
+	for knot := len(rope) - 1; knot >= 0; knot-- {
+		setCell(frame, bounds, rope[knot], knotLabel(knot, len(rope)))
+	}
+	return frameToString(frame)
+}
+
+func renderTailVisits(tailPoints map[Point]struct{}, bounds Bounds) string {
+	frame := emptyFrame(bounds)
+	for point := range tailPoints {
+		setCell(frame, bounds, point, '#')
+	}
+	setCell(frame, bounds, Point{x: 0, y: 0}, 's')
+	return frameToString(frame)
+}
+
+// move numbers start at 1 like the input lines, nil selects every move
+func parseMoveSelection(moveList string) (map[int]struct{}, error) {
+	if moveList == "" {
+		return nil, nil
+	}
+	selected := make(map[int]struct{})
+	for _, field := range strings.Split(moveList, ",") {
+		moveNumber, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || moveNumber < 1 {
+			return nil, fmt.Errorf("invalid move number %q", field)
+		}
+		selected[moveNumber] = struct{}{}
+	}
+	return selected, nil
+}
+
+// renderRopeMotion draws the rope after each head step, or only after each
+// whole move, then the points the tail visited
+func renderRopeMotion(
+	output io.Writer,
+	moves []Move,
+	knotCount int,
+	traversedPoints []map[Point]struct{},
+	everyStep bool,
+	selectedMoves map[int]struct{},
+) {
+	writer := bufio.NewWriter(output)
+	bounds := findBounds(traversedPoints)
+	fmt.Fprintf(writer, "== %d knots ==\n\n", knotCount)
+	simulateRope(moves, knotCount, func(moveIndex int, step int, rope []Point) {
+		if selectedMoves != nil {
+			if _, selected := selectedMoves[moveIndex+1]; !selected {
+				return
+			}
+		}
+		move := moves[moveIndex]
+		lastStep := step == move.distance-1
+		if !everyStep && !lastStep {
+			return
+		}
+		if step == 0 || !everyStep {
+			fmt.Fprintf(writer, "== %s %d ==\n\n", move.direction, move.distance)
+		}
+		fmt.Fprintf(writer, "%s\n\n", renderRope(rope, bounds))
+	})
+	fmt.Fprintf(writer, "== tail visits ==\n\n%s\n\n", renderTailVisits(traversedPoints[knotCount-1], bounds))
+	writer.Flush()
+}
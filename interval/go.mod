@@ -0,0 +1,3 @@
+module aoc2022/interval
+
+go 1.18
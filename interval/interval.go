@@ -0,0 +1,73 @@
+// Package interval is closed integer interval arithmetic shared between days.
+package interval
+
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Interval is the closed range [Min, Max], so it's never empty as long as
+// Min <= Max.
+type Interval[T Integer] struct {
+	Min T
+	Max T
+}
+
+// New orders the bounds so the interval is always valid.
+func New[T Integer](a T, b T) Interval[T] {
+	if a > b {
+		a, b = b, a
+	}
+	return Interval[T]{Min: a, Max: b}
+}
+
+// Len is the number of integers in the interval.
+func (i Interval[T]) Len() T {
+	return i.Max - i.Min + 1
+}
+
+func (i Interval[T]) Contains(x T) bool {
+	return i.Min <= x && x <= i.Max
+}
+
+func (i Interval[T]) ContainsInterval(other Interval[T]) bool {
+	return i.Min <= other.Min && other.Max <= i.Max
+}
+
+// Overlaps is true when the intervals share at least one integer.
+func (i Interval[T]) Overlaps(other Interval[T]) bool {
+	return i.Min <= other.Max && other.Min <= i.Max
+}
+
+// Adjacent is true when the intervals don't overlap but there's no integer
+// between them, e.g. 2-3 and 4-5. Written without Max+1 so it can't overflow.
+func (i Interval[T]) Adjacent(other Interval[T]) bool {
+	if i.Max < other.Min {
+		return other.Min-i.Max == 1
+	}
+	if other.Max < i.Min {
+		return i.Min-other.Max == 1
+	}
+	return false
+}
+
+func (i Interval[T]) Intersect(other Interval[T]) (Interval[T], bool) {
+	if !i.Overlaps(other) {
+		return Interval[T]{}, false
+	}
+	return Interval[T]{Min: max(i.Min, other.Min), Max: min(i.Max, other.Max)}, true
+}
+
+func min[T Integer](a T, b T) T {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max[T Integer](a T, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,128 @@
+package interval
+
+import (
+	"sort"
+)
+
+// IntervalSet keeps its intervals sorted, disjoint and non-adjacent, so
+// overlapping or touching inserts are merged into one interval.
+type IntervalSet[T Integer] struct {
+	intervals []Interval[T]
+}
+
+func NewSet[T Integer](intervals ...Interval[T]) IntervalSet[T] {
+	set := IntervalSet[T]{}
+	for _, i := range intervals {
+		set.Insert(i)
+	}
+	return set
+}
+
+// Intervals returns a copy of the merged intervals in ascending order.
+func (s IntervalSet[T]) Intervals() []Interval[T] {
+	return append([]Interval[T](nil), s.intervals...)
+}
+
+func (s IntervalSet[T]) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// Insert adds i, merging it with anything it overlaps or touches.
+func (s *IntervalSet[T]) Insert(i Interval[T]) {
+	// first interval that could merge with i
+	start := sort.Search(len(s.intervals), func(j int) bool {
+		return s.intervals[j].Max >= i.Min || s.intervals[j].Adjacent(i)
+	})
+	end := start
+	for end < len(s.intervals) && (s.intervals[end].Overlaps(i) || s.intervals[end].Adjacent(i)) {
+		i.Min = min(i.Min, s.intervals[end].Min)
+		i.Max = max(i.Max, s.intervals[end].Max)
+		end++
+	}
+	merged := make([]Interval[T], 0, len(s.intervals)-(end-start)+1)
+	merged = append(merged, s.intervals[:start]...)
+	merged = append(merged, i)
+	merged = append(merged, s.intervals[end:]...)
+	s.intervals = merged
+}
+
+func (s IntervalSet[T]) Union(other IntervalSet[T]) IntervalSet[T] {
+	union := IntervalSet[T]{intervals: s.Intervals()}
+	for _, i := range other.intervals {
+		union.Insert(i)
+	}
+	return union
+}
+
+func (s IntervalSet[T]) Intersection(other IntervalSet[T]) IntervalSet[T] {
+	intersection := IntervalSet[T]{}
+	a, b := 0, 0
+	for a < len(s.intervals) && b < len(other.intervals) {
+		if i, ok := s.intervals[a].Intersect(other.intervals[b]); ok {
+			intersection.intervals = append(intersection.intervals, i)
+		}
+		if s.intervals[a].Max < other.intervals[b].Max {
+			a++
+		} else {
+			b++
+		}
+	}
+	return intersection
+}
+
+// Difference is everything in s that isn't in other.
+func (s IntervalSet[T]) Difference(other IntervalSet[T]) IntervalSet[T] {
+	difference := IntervalSet[T]{}
+	for _, i := range s.intervals {
+		difference.intervals = append(difference.intervals, other.Gaps(i)...)
+	}
+	return difference
+}
+
+// Contains finds the last interval starting at or before x.
+func (s IntervalSet[T]) Contains(x T) bool {
+	j := sort.Search(len(s.intervals), func(j int) bool {
+		return s.intervals[j].Min > x
+	})
+	return j > 0 && s.intervals[j-1].Contains(x)
+}
+
+// ContainsInterval is only true when i is covered without any gaps, which
+// means a single merged interval has to cover it.
+func (s IntervalSet[T]) ContainsInterval(i Interval[T]) bool {
+	j := sort.Search(len(s.intervals), func(j int) bool {
+		return s.intervals[j].Min > i.Min
+	})
+	return j > 0 && s.intervals[j-1].ContainsInterval(i)
+}
+
+// Len is the total number of integers covered.
+func (s IntervalSet[T]) Len() T {
+	var total T
+	for _, i := range s.intervals {
+		total += i.Len()
+	}
+	return total
+}
+
+// Gaps lists the parts of within that aren't covered by the set.
+func (s IntervalSet[T]) Gaps(within Interval[T]) []Interval[T] {
+	gaps := make([]Interval[T], 0)
+	next := within.Min
+	for _, i := range s.intervals {
+		if i.Max < next {
+			continue
+		}
+		if i.Min > within.Max {
+			break
+		}
+		if i.Min > next {
+			gaps = append(gaps, Interval[T]{Min: next, Max: i.Min - 1})
+		}
+		if i.Max >= within.Max {
+			return gaps
+		}
+		next = i.Max + 1
+	}
+	return append(gaps, Interval[T]{Min: next, Max: within.Max})
+}
@@ -0,0 +1,146 @@
+package interval
+
+import (
+	"testing"
+)
+
+func intervals(bounds ...int) []Interval[int] {
+	result := make([]Interval[int], 0, len(bounds)/2)
+	for i := 0; i+1 < len(bounds); i += 2 {
+		result = append(result, Interval[int]{Min: bounds[i], Max: bounds[i+1]})
+	}
+	return result
+}
+
+// nil and empty slices are treated as equal, unlike reflect.DeepEqual
+func sameIntervals(a []Interval[int], b []Interval[int]) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestInsert(t *testing.T) {
+	tests := []struct {
+		name    string
+		inserts []Interval[int]
+		want    []Interval[int]
+	}{
+		{"disjoint", intervals(10, 12, 1, 3, 5, 7), intervals(1, 3, 5, 7, 10, 12)},
+		{"overlapping", intervals(1, 5, 4, 8), intervals(1, 8)},
+		{"adjacent after", intervals(1, 3, 4, 6), intervals(1, 6)},
+		{"adjacent before", intervals(4, 6, 1, 3), intervals(1, 6)},
+		{"contained", intervals(1, 10, 3, 4), intervals(1, 10)},
+		{"bridges several", intervals(1, 2, 5, 6, 9, 10, 13, 14, 3, 11), intervals(1, 11, 13, 14)},
+		{"gap of one stays", intervals(1, 3, 5, 7), intervals(1, 3, 5, 7)},
+		{"single points", intervals(2, 2, 4, 4, 3, 3), intervals(2, 4)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			set := NewSet(test.inserts...)
+			if got := set.Intervals(); !sameIntervals(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGaps(t *testing.T) {
+	set := NewSet(intervals(3, 5, 8, 9)...)
+	tests := []struct {
+		name   string
+		within Interval[int]
+		want   []Interval[int]
+	}{
+		{"gaps at both edges", New(0, 12), intervals(0, 2, 6, 7, 10, 12)},
+		{"starts inside", New(4, 12), intervals(6, 7, 10, 12)},
+		{"ends inside", New(0, 8), intervals(0, 2, 6, 7)},
+		{"starts on edge", New(3, 7), intervals(6, 7)},
+		{"ends on edge", New(6, 9), intervals(6, 7)},
+		{"fully covered", New(3, 5), intervals()},
+		{"before everything", New(0, 1), intervals(0, 1)},
+		{"after everything", New(11, 12), intervals(11, 12)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := set.Gaps(test.within); !sameIntervals(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	empty := NewSet[int]()
+	if got, want := empty.Gaps(New(0, 4)), intervals(0, 4); !sameIntervals(got, want) {
+		t.Errorf("empty set: got %v, want %v", got, want)
+	}
+}
+
+func TestDifference(t *testing.T) {
+	set := NewSet(intervals(1, 4, 8, 12)...)
+	empty := NewSet[int]()
+	tests := []struct {
+		name string
+		a    IntervalSet[int]
+		b    IntervalSet[int]
+		want []Interval[int]
+	}{
+		{"minus empty", set, empty, intervals(1, 4, 8, 12)},
+		{"empty minus", empty, set, intervals()},
+		{"minus itself", set, set, intervals()},
+		{"punch holes", set, NewSet(intervals(2, 2, 10, 13)...), intervals(1, 1, 3, 4, 8, 9)},
+		{"minus adjacent", set, NewSet(intervals(5, 7)...), intervals(1, 4, 8, 12)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.a.Difference(test.b).Intervals()
+			if !sameIntervals(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	a := NewSet(intervals(1, 4, 8, 12, 15, 20)...)
+	tests := []struct {
+		name string
+		b    IntervalSet[int]
+		want []Interval[int]
+	}{
+		{"empty", NewSet[int](), intervals()},
+		{"spans several", NewSet(intervals(3, 9, 11, 16)...), intervals(3, 4, 8, 9, 11, 12, 15, 16)},
+		{"adjacent only", NewSet(intervals(5, 7, 13, 14)...), intervals()},
+		{"touching endpoints", NewSet(intervals(4, 4, 12, 15)...), intervals(4, 4, 12, 12, 15, 15)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := a.Intersection(test.b).Intervals()
+			if !sameIntervals(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLenAndContains(t *testing.T) {
+	set := NewSet(intervals(1, 4, 6, 6)...)
+	if got := set.Len(); got != 5 {
+		t.Errorf("Len() = %d, want 5", got)
+	}
+	for x, want := range map[int]bool{0: false, 1: true, 4: true, 5: false, 6: true, 7: false} {
+		if got := set.Contains(x); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", x, got, want)
+		}
+	}
+	if set.ContainsInterval(New(3, 6)) {
+		t.Errorf("ContainsInterval(3-6) should be false across the gap at 5")
+	}
+	if !set.ContainsInterval(New(2, 4)) {
+		t.Errorf("ContainsInterval(2-4) should be true")
+	}
+}